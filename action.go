@@ -1,9 +1,9 @@
 package zabbix
 
 import (
+	"encoding/json"
+	"fmt"
 	"strconv"
-
-	"github.com/mitchellh/mapstructure"
 )
 
 type (
@@ -15,9 +15,10 @@ type (
 )
 
 const (
-	ANDOR EvalType = 0
-	AND   EvalType = 1
-	OR    EvalType = 2
+	ANDOR             EvalType = 0
+	AND               EvalType = 1
+	OR                EvalType = 2
+	CUSTOM_EXPRESSION EvalType = 3
 )
 
 const (
@@ -32,6 +33,8 @@ const (
 	ENABLE_HOST             OperationType = 8
 	DISABLE_HOST            OperationType = 9
 	SET_HOST_INVENTORY_MODE OperationType = 10
+	NOTIFY_ALL_INVOLVED     OperationType = 11 // recovery operation, Zabbix 3.2+
+	NOTIFY_UPDATE_INVOLVED  OperationType = 12 // acknowledge (update) operation, Zabbix 3.4+
 )
 
 const (
@@ -53,11 +56,61 @@ const (
 	ZABBIX_PROXY  ExecuteOn = 2
 )
 
+type (
+	ConditionType     int
+	ConditionOperator int
+)
+
+// Types of action conditions: https://www.zabbix.com/documentation/4.2/manual/api/reference/action/object#action_filter_condition
+const (
+	ConditionHostGroup              ConditionType = 0
+	ConditionHost                   ConditionType = 1
+	ConditionTrigger                ConditionType = 2
+	ConditionTriggerName            ConditionType = 3 // event name since Zabbix 5.4
+	ConditionTriggerSeverity        ConditionType = 4
+	ConditionTimePeriod             ConditionType = 6
+	ConditionHostIP                 ConditionType = 7
+	ConditionDiscoveredServiceType  ConditionType = 8
+	ConditionDiscoveredServicePort  ConditionType = 9
+	ConditionDiscoveryStatus        ConditionType = 10
+	ConditionUptimeDowntimeDuration ConditionType = 11
+	ConditionReceivedValue          ConditionType = 12
+	ConditionHostTemplate           ConditionType = 13
+	ConditionEventAcknowledged      ConditionType = 14 // only for operation conditions
+	ConditionApplication            ConditionType = 15 // before Zabbix 5.4
+	ConditionSuppressed             ConditionType = 16
+	ConditionDiscoveryRule          ConditionType = 18
+	ConditionDiscoveryCheck         ConditionType = 19
+	ConditionProxy                  ConditionType = 20
+	ConditionDiscoveryObject        ConditionType = 21
+	ConditionHostName               ConditionType = 22
+	ConditionEventType              ConditionType = 23
+	ConditionHostMetadata           ConditionType = 24
+	ConditionEventTag               ConditionType = 25
+	ConditionEventTagValue          ConditionType = 26
+)
+
+const (
+	OperatorEqual          ConditionOperator = 0
+	OperatorNotEqual       ConditionOperator = 1
+	OperatorLike           ConditionOperator = 2
+	OperatorNotLike        ConditionOperator = 3
+	OperatorIn             ConditionOperator = 4
+	OperatorGreaterOrEqual ConditionOperator = 5
+	OperatorLessOrEqual    ConditionOperator = 6
+	OperatorNotIn          ConditionOperator = 7
+	OperatorMatches        ConditionOperator = 8 // Zabbix 6.0+
+	OperatorNotMatches     ConditionOperator = 9 // Zabbix 6.0+
+	OperatorYes            ConditionOperator = 10
+	OperatorNo             ConditionOperator = 11
+)
+
 // https://www.zabbix.com/documentation/4.2/manual/appendix/api/action/definitions
+// Since Zabbix 5.0 default messages are defined by media types, not by actions.
 type Action struct {
 	ActionId        string     `json:"actionid,omitempty"`
 	EscPeriod       string     `json:"esc_period,omitempty"`
-	EventSource     SourceType `json:"eventsource"`
+	EventSource     SourceType `json:"eventsource"` // can't be updated
 	Name            string     `json:"name"`
 	DefLongdata     string     `json:"def_longdata,omitempty"`
 	DefShortdata    string     `json:"def_shortdata,omitempty"`
@@ -65,9 +118,15 @@ type Action struct {
 	RShortData      string     `json:"r_shortdata,omitempty"`
 	AckLongData     string     `json:"ack_longdata,omitempty"`
 	AckShortData    string     `json:"ack_shortdata,omitempty"`
-	Status          int        `json:"status,omitempty"`
+	Status          int        `json:"status"` // 0 - enabled, 1 - disabled
 	PauseSuppressed int        `json:"pause_suppressed,omitempty"`
-	Operations      Operations `json:"operations"`
+	Filter          *Filter    `json:"filter,omitempty"`
+	Operations      Operations `json:"operations,omitempty"`
+
+	// Operations of OperationType SEND_MESSAGE, REMOTE_COMMAND and NOTIFY_ALL_INVOLVED, without escalation.
+	RecoveryOperations    Operations `json:"recovery_operations,omitempty"`    // Zabbix 3.2+
+	AcknowledgeOperations Operations `json:"acknowledge_operations,omitempty"` // Zabbix 3.4 - 5.0
+	UpdateOperations      Operations `json:"update_operations,omitempty"`      // Zabbix 5.2+
 }
 
 type Actions []Action
@@ -76,6 +135,26 @@ type ActionId struct {
 	ActionId string `json:"actionid"`
 }
 
+// Filter selects events handled by action: https://www.zabbix.com/documentation/4.2/manual/api/reference/action/object#action_filter
+type Filter struct {
+	EvalType    EvalType   `json:"evaltype"`
+	Formula     string     `json:"formula,omitempty"`      // for CUSTOM_EXPRESSION, like "A and (B or C)"
+	EvalFormula string     `json:"eval_formula,omitempty"` // read-only
+	Conditions  Conditions `json:"conditions"`
+}
+
+// Condition of action filter.
+type Condition struct {
+	ConditionId   string            `json:"conditionid,omitempty"`
+	ConditionType ConditionType     `json:"conditiontype"`
+	Operator      ConditionOperator `json:"operator"`
+	Value         string            `json:"value"`
+	Value2        string            `json:"value2,omitempty"`    // tag name for ConditionEventTagValue
+	FormulaId     string            `json:"formulaid,omitempty"` // for CUSTOM_EXPRESSION
+}
+
+type Conditions []Condition
+
 type Operation struct {
 	OperationId   string        `json:"operationid,omitempty"`
 	OperationType OperationType `json:"operationtype"`
@@ -84,16 +163,16 @@ type Operation struct {
 	EscStepFrom   int           `json:"esc_step_from,omitempty"`
 	EscStepTo     int           `json:"esc_step_to,omitempty"`
 	EvalType      EvalType      `json:"evaltype,omitempty"`
-	OpCommand     OpCommand     `json:"opcommand,omitempty"`
+	OpCommand     *OpCommand    `json:"opcommand,omitempty"`
 	OpCommandGrp  OpCommandGrps `json:"opcommand_grp,omitempty"`
 	OpCommandHst  OpCommandHsts `json:"opcommand_hst,omitempty"`
 	OpConditions  OpConditions  `json:"opconditions,omitempty"`
 	OpGroup       OpGroups      `json:"opgroup,omitempty"`
-	OpMessage     OpMessage     `json:"opmessage,omitempty"`
+	OpMessage     *OpMessage    `json:"opmessage,omitempty"`
 	OpMessageGrp  OpMessageGrps `json:"opmessage_grp,omitempty"`
 	OpMessageUsr  OpMessageUsrs `json:"opmessage_usr,omitempty"`
 	OpTemplate    OpTemplates   `json:"optemplate,omitempty"`
-	OpInventory   OpInventory   `json:"opinventory,omitempty"`
+	OpInventory   *OpInventory  `json:"opinventory,omitempty"`
 }
 
 type Operations []Operation
@@ -106,7 +185,7 @@ type OpCommand struct {
 	OperationId string      `json:"operationid,omitempty"`
 	Command     string      `json:"command,omitempty"`
 	Type        CommandType `json:"type"`
-	AuthType    int         `json:"authtype,omitempty"`
+	AuthType    AuthType    `json:"authtype,omitempty"`
 	ExecuteOn   ExecuteOn   `json:"execute_on,omitempty"`
 	Password    string      `json:"password,omitempty"`
 	Port        string      `json:"port,omitempty"`
@@ -117,35 +196,35 @@ type OpCommand struct {
 }
 
 type OpCommandGrp struct {
-	OpCommandGrpid string      `json:"opcommand_grpid,omitempty"`
-	OperationId    OperationId `json:"operationid,omitempty"`
-	GroupId        string      `json:"groupid,omitempty"`
+	OpCommandGrpid string `json:"opcommand_grpid,omitempty"`
+	OperationId    string `json:"operationid,omitempty"`
+	GroupId        string `json:"groupid,omitempty"`
 }
 
 type OpCommandGrps []OpCommandGrp
 
 type OpCommandHst struct {
-	OpCommandHstid string      `json:"opcommand_hstid,omitempty"`
-	OperationId    OperationId `json:"operationid,omitempty"`
-	HostId         HostId      `json:"hostid,omitempty"`
+	OpCommandHstid string `json:"opcommand_hstid,omitempty"`
+	OperationId    string `json:"operationid,omitempty"`
+	HostId         string `json:"hostid,omitempty"` // "0" for the host of event
 }
 type OpCommandHsts []OpCommandHst
 
 type Opcondition struct {
-	OpConditionId   string `json:"opconditionid,omitempty"`
-	OpConditionType int    `json:"conditiontype"`
+	OpConditionId   string        `json:"opconditionid,omitempty"`
+	OpConditionType ConditionType `json:"conditiontype"`
 	// Possible values:
 	// 14 - event acknowledged.
-	Value       string      `json:"value,omitempty"`
-	OperationId OperationId `json:"operationid,omitempty"`
-	Operator    int         `json:"operator,omitempty"`
+	Value       string            `json:"value,omitempty"`
+	OperationId string            `json:"operationid,omitempty"`
+	Operator    ConditionOperator `json:"operator,omitempty"`
 }
 
 type OpConditions []Opcondition
 
 type OpGroup struct {
-	//OperationId OperationId `json:"operationid"`
-	GroupId string `json:"groupid,omitempty"`
+	OperationId string `json:"operationid,omitempty"`
+	GroupId     string `json:"groupid,omitempty"`
 }
 type OpGroups []OpGroup
 
@@ -161,8 +240,8 @@ type OpMessage struct {
 }
 
 type OpMessageGrp struct {
-	OperationId string      `json:"operationid,omitempty"`
-	UsrGrpId    HostGroupId `json:"groupid,omitempty"`
+	OperationId string `json:"operationid,omitempty"`
+	UsrGrpId    string `json:"usrgrpid,omitempty"`
 }
 type OpMessageGrps []OpMessageGrp
 
@@ -173,8 +252,8 @@ type OpMessageUsr struct {
 type OpMessageUsrs []OpMessageUsr
 
 type OpTemplate struct {
-	OperationId string     `json:"operationid,omitempty"`
-	TemplateId  TemplateId `json:"templateid,omitempty"`
+	OperationId string `json:"operationid,omitempty"`
+	TemplateId  string `json:"templateid,omitempty"`
 }
 type OpTemplates []OpTemplate
 
@@ -183,18 +262,195 @@ type OpInventory struct {
 	InventoryMode string `json:"inventory_mode,omitempty"`
 }
 
+var (
+	// Types of operations allowed for event sources.
+	sourceOperationTypes = map[SourceType][]OperationType{
+		SourceTrigger: {SEND_MESSAGE, REMOTE_COMMAND},
+		SourceDiscoveryRule: {SEND_MESSAGE, REMOTE_COMMAND, ADD_HOST, REMOVE_HOST, ADD_TO_HOST_GROUP, REMOVE_FROM_HOST_GROUP,
+			LINK_TO_TEMPLATE, UNLINK_FROM_TEMPLATE, ENABLE_HOST, DISABLE_HOST, SET_HOST_INVENTORY_MODE},
+		SourceActiveAgent: {SEND_MESSAGE, REMOTE_COMMAND, ADD_HOST, REMOVE_HOST, ADD_TO_HOST_GROUP, REMOVE_FROM_HOST_GROUP,
+			LINK_TO_TEMPLATE, UNLINK_FROM_TEMPLATE, ENABLE_HOST, DISABLE_HOST, SET_HOST_INVENTORY_MODE},
+		SourceInternal: {SEND_MESSAGE},
+	}
+	sourceRecoveryOperationTypes = map[SourceType][]OperationType{
+		SourceTrigger:  {SEND_MESSAGE, REMOTE_COMMAND, NOTIFY_ALL_INVOLVED},
+		SourceInternal: {SEND_MESSAGE, NOTIFY_ALL_INVOLVED},
+	}
+	sourceUpdateOperationTypes = map[SourceType][]OperationType{
+		SourceTrigger: {SEND_MESSAGE, REMOTE_COMMAND, NOTIFY_UPDATE_INVOLVED},
+	}
+
+	// Types of filter conditions allowed for event sources.
+	sourceConditionTypes = map[SourceType][]ConditionType{
+		SourceTrigger: {ConditionHostGroup, ConditionHost, ConditionTrigger, ConditionTriggerName, ConditionTriggerSeverity,
+			ConditionTimePeriod, ConditionHostTemplate, ConditionApplication, ConditionSuppressed, ConditionEventTag, ConditionEventTagValue},
+		SourceDiscoveryRule: {ConditionHostIP, ConditionDiscoveredServiceType, ConditionDiscoveredServicePort, ConditionDiscoveryStatus,
+			ConditionUptimeDowntimeDuration, ConditionReceivedValue, ConditionDiscoveryRule, ConditionDiscoveryCheck, ConditionProxy, ConditionDiscoveryObject},
+		SourceActiveAgent: {ConditionProxy, ConditionHostName, ConditionHostMetadata},
+		SourceInternal: {ConditionHostGroup, ConditionHost, ConditionHostTemplate, ConditionApplication, ConditionEventType,
+			ConditionEventTag, ConditionEventTagValue},
+	}
+)
+
+// Validate checks that conditions and operations are allowed for event source of action,
+// and that operations have fields required by their types. Actions of unknown event sources are not checked.
+func (a *Action) Validate() error {
+	if a.Name == "" {
+		return fmt.Errorf("Action name is not set.")
+	}
+	if _, known := sourceOperationTypes[a.EventSource]; !known {
+		return nil
+	}
+
+	if f := a.Filter; f != nil {
+		for i, c := range f.Conditions {
+			if !containsConditionType(sourceConditionTypes[a.EventSource], c.ConditionType) {
+				return fmt.Errorf("Action %q has condition %d of type %d not allowed for event source %d.", a.Name, i+1, c.ConditionType, a.EventSource)
+			}
+			if f.EvalType == CUSTOM_EXPRESSION && c.FormulaId == "" {
+				return fmt.Errorf("Action %q has condition %d without formula ID.", a.Name, i+1)
+			}
+		}
+		if f.EvalType == CUSTOM_EXPRESSION && f.Formula == "" {
+			return fmt.Errorf("Action %q has custom expression filter without formula.", a.Name)
+		}
+	}
+
+	if len(a.Operations)+len(a.RecoveryOperations)+len(a.AcknowledgeOperations)+len(a.UpdateOperations) == 0 {
+		return fmt.Errorf("Action %q has no operations.", a.Name)
+	}
+	for _, ops := range []struct {
+		name       string
+		operations Operations
+		types      []OperationType
+	}{
+		{"operation", a.Operations, sourceOperationTypes[a.EventSource]},
+		{"recovery operation", a.RecoveryOperations, sourceRecoveryOperationTypes[a.EventSource]},
+		{"acknowledge operation", a.AcknowledgeOperations, sourceUpdateOperationTypes[a.EventSource]},
+		{"update operation", a.UpdateOperations, sourceUpdateOperationTypes[a.EventSource]},
+	} {
+		for i := range ops.operations {
+			op := &ops.operations[i]
+			allowed := false
+			for _, t := range ops.types {
+				allowed = allowed || t == op.OperationType
+			}
+			if !allowed {
+				return fmt.Errorf("Action %q has %s %d of type %d not allowed for event source %d.", a.Name, ops.name, i+1, op.OperationType, a.EventSource)
+			}
+			if err := op.validate(a.EventSource); err != nil {
+				return fmt.Errorf("Action %q has invalid %s %d: %s.", a.Name, ops.name, i+1, err)
+			}
+		}
+	}
+	return nil
+}
+
+func containsConditionType(types []ConditionType, t ConditionType) bool {
+	for _, c := range types {
+		if c == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Checks fields required by operation type.
+func (op *Operation) validate(source SourceType) error {
+	if op.EscStepFrom < 0 || op.EscStepTo < 0 || (op.EscStepTo != 0 && op.EscStepTo < op.EscStepFrom) {
+		return fmt.Errorf("unexpected escalation steps %d-%d", op.EscStepFrom, op.EscStepTo)
+	}
+	for _, c := range op.OpConditions {
+		if source != SourceTrigger || c.OpConditionType != ConditionEventAcknowledged {
+			return fmt.Errorf("unexpected condition type %d", c.OpConditionType)
+		}
+	}
+
+	switch op.OperationType {
+	case SEND_MESSAGE:
+		if op.OpMessage == nil {
+			return fmt.Errorf("message is not set")
+		}
+		if len(op.OpMessageGrp) == 0 && len(op.OpMessageUsr) == 0 {
+			return fmt.Errorf("no user groups and users to send message to")
+		}
+	case NOTIFY_ALL_INVOLVED, NOTIFY_UPDATE_INVOLVED:
+		if op.OpMessage == nil {
+			return fmt.Errorf("message is not set")
+		}
+	case REMOTE_COMMAND:
+		c := op.OpCommand
+		if c == nil {
+			return fmt.Errorf("command is not set")
+		}
+		if len(op.OpCommandHst) == 0 && len(op.OpCommandGrp) == 0 {
+			return fmt.Errorf("no hosts and host groups to execute command on")
+		}
+		switch {
+		case c.Type == GLOBAL_SCRIPT && c.ScriptId == "":
+			return fmt.Errorf("global script is not set")
+		case c.Type != GLOBAL_SCRIPT && c.Command == "":
+			return fmt.Errorf("command is empty")
+		case (c.Type == SSH || c.Type == TELNET) && c.Username == "":
+			return fmt.Errorf("user name is not set")
+		case c.Type == SSH && c.AuthType == PUBLIC_KEY && (c.PublicKey == "" || c.PrivateKey == ""):
+			return fmt.Errorf("public and private keys are not set")
+		}
+	case ADD_TO_HOST_GROUP, REMOVE_FROM_HOST_GROUP:
+		if len(op.OpGroup) == 0 {
+			return fmt.Errorf("no host groups")
+		}
+	case LINK_TO_TEMPLATE, UNLINK_FROM_TEMPLATE:
+		if len(op.OpTemplate) == 0 {
+			return fmt.Errorf("no templates")
+		}
+	case SET_HOST_INVENTORY_MODE:
+		if op.OpInventory == nil {
+			return fmt.Errorf("inventory mode is not set")
+		}
+	}
+	return nil
+}
+
 // Wrapper for action.get: https://www.zabbix.com/documentation/4.2/manual/appendix/api/action/get
+// Filter and all operations supported by Zabbix version are selected by default.
 func (api *API) ActionGet(params Params) (res Actions, err error) {
 	if _, present := params["output"]; !present {
 		params["output"] = "extend"
 	}
+	if _, present := params["selectFilter"]; !present {
+		params["selectFilter"] = "extend"
+	}
+	if _, present := params["selectOperations"]; !present {
+		params["selectOperations"] = "extend"
+	}
+	for _, s := range []struct {
+		param        string
+		major, minor int
+	}{
+		{"selectRecoveryOperations", 3, 2},
+		{"selectUpdateOperations", 5, 2},
+		{"selectAcknowledgeOperations", 3, 4}, // renamed in Zabbix 5.2
+	} {
+		_, present := params[s.param]
+		if !present {
+			if present, err = api.versionAtLeast(s.major, s.minor); err != nil {
+				return
+			}
+			if present {
+				params[s.param] = "extend"
+			}
+		}
+		if present && s.param == "selectUpdateOperations" {
+			break
+		}
+	}
 	response, err := api.CallWithError("action.get", params)
 	if err != nil {
 		return
 	}
 
-	mapstructure.Decode(response.Result.([]interface{}), &res)
-
+	err = decode(response.Result.([]interface{}), &res)
 	return
 }
 
@@ -215,7 +471,13 @@ func (api *API) ActionGetById(id string) (res *Action, err error) {
 }
 
 // Wrapper for action.create: https://www.zabbix.com/documentation/4.2/manual/appendix/api/action/create
+// Actions are validated before the call.
 func (api *API) ActionsCreate(actions Actions) (err error) {
+	for i := range actions {
+		if err = actions[i].Validate(); err != nil {
+			return
+		}
+	}
 	response, err := api.CallWithError("action.create", actions)
 	if err != nil {
 		return
@@ -224,8 +486,43 @@ func (api *API) ActionsCreate(actions Actions) (err error) {
 	result := response.Result.(map[string]interface{})
 	actionsids := result["actionids"].([]interface{})
 	for i, id := range actionsids {
-		actionid := strconv.FormatFloat(id.(float64), 'f', 0, 64)
-		actions[i].ActionId = actionid
+		switch id := id.(type) {
+		case string:
+			actions[i].ActionId = id
+		case float64: // old Zabbix versions
+			actions[i].ActionId = strconv.FormatFloat(id, 'f', 0, 64)
+		}
+	}
+	return
+}
+
+// Wrapper for action.update: https://www.zabbix.com/documentation/4.2/manual/api/reference/action/update
+// EventSource and read-only fields are not sent. Non-empty Filter and operations replace existing ones.
+func (api *API) ActionsUpdate(actions Actions) (err error) {
+	params := make([]map[string]interface{}, len(actions))
+	for i, a := range actions {
+		b, err := json.Marshal(a)
+		if err != nil {
+			return err
+		}
+		if err = json.Unmarshal(b, &params[i]); err != nil {
+			return err
+		}
+		delete(params[i], "eventsource")
+		if f, ok := params[i]["filter"].(map[string]interface{}); ok {
+			delete(f, "eval_formula")
+		}
+	}
+
+	response, err := api.CallWithError("action.update", params)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	actionids := result["actionids"].([]interface{})
+	if len(actions) != len(actionids) {
+		err = &ExpectedMore{len(actions), len(actionids)}
 	}
 	return
 }
@@ -0,0 +1,333 @@
+package zabbix
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// ActionBuilder builds Action step by step:
+//
+//	a, err := NewActionBuilder("Database problems", SourceTrigger).
+//		Severity(OperatorGreaterOrEqual, High).
+//		HostGroups(dbGroupId).
+//		EscalationPeriod(5 * time.Minute).
+//		SendToUserGroups(mediaTypeId, dbaGroupId).
+//		After(10 * time.Minute).RunScript(restartScriptId, dbHostId).
+//		OnRecovery().NotifyAllInvolved().
+//		Create(api)
+//
+// Operations are added to problem operations, or to recovery or update operations after OnRecovery or OnUpdate.
+// Errors are collected and returned by Build or Create.
+type ActionBuilder struct {
+	a        Action
+	mode     *Operations // where operations are added
+	from, to int         // escalation steps of problem operations
+	err      error
+}
+
+// NewActionBuilder returns builder of enabled action for events of given source.
+// Problem operations are executed at the first escalation step by default.
+func NewActionBuilder(name string, source SourceType) *ActionBuilder {
+	b := &ActionBuilder{a: Action{Name: name, EventSource: source}, from: 1, to: 1}
+	b.mode = &b.a.Operations
+	return b
+}
+
+func (b *ActionBuilder) fail(err error) *ActionBuilder {
+	if b.err == nil {
+		b.err = err
+	}
+	return b
+}
+
+// Disabled makes action disabled.
+func (b *ActionBuilder) Disabled() *ActionBuilder {
+	b.a.Status = 1
+	return b
+}
+
+// PauseSuppressed pauses escalations during maintenance (Zabbix 4.0+).
+func (b *ActionBuilder) PauseSuppressed() *ActionBuilder {
+	b.a.PauseSuppressed = 1
+	return b
+}
+
+// Condition adds condition to action filter.
+func (b *ActionBuilder) Condition(t ConditionType, operator ConditionOperator, value string) *ActionBuilder {
+	if b.a.Filter == nil {
+		b.a.Filter = &Filter{}
+	}
+	b.a.Filter.Conditions = append(b.a.Filter.Conditions, Condition{ConditionType: t, Operator: operator, Value: value})
+	return b
+}
+
+// Severity adds condition on trigger severity, like "severity >= High".
+func (b *ActionBuilder) Severity(operator ConditionOperator, severity PriorityType) *ActionBuilder {
+	return b.Condition(ConditionTriggerSeverity, operator, strconv.Itoa(int(severity)))
+}
+
+// HostGroups adds condition on host group for each group ID.
+func (b *ActionBuilder) HostGroups(ids ...string) *ActionBuilder {
+	for _, id := range ids {
+		b.Condition(ConditionHostGroup, OperatorEqual, id)
+	}
+	return b
+}
+
+// Hosts adds condition on host for each host ID.
+func (b *ActionBuilder) Hosts(ids ...string) *ActionBuilder {
+	for _, id := range ids {
+		b.Condition(ConditionHost, OperatorEqual, id)
+	}
+	return b
+}
+
+// Tag adds condition on value of event tag.
+func (b *ActionBuilder) Tag(tag string, operator ConditionOperator, value string) *ActionBuilder {
+	b.Condition(ConditionEventTagValue, operator, value)
+	b.a.Filter.Conditions[len(b.a.Filter.Conditions)-1].Value2 = tag
+	return b
+}
+
+// MatchAny makes action handle events matching any condition, instead of all conditions of different types.
+func (b *ActionBuilder) MatchAny() *ActionBuilder {
+	if b.a.Filter == nil {
+		b.a.Filter = &Filter{}
+	}
+	b.a.Filter.EvalType = OR
+	return b
+}
+
+// Formula sets custom expression of conditions, like "A and (B or C)".
+// Conditions are named by letters in order they are added.
+func (b *ActionBuilder) Formula(formula string) *ActionBuilder {
+	if b.a.Filter == nil {
+		b.a.Filter = &Filter{}
+	}
+	b.a.Filter.EvalType, b.a.Filter.Formula = CUSTOM_EXPRESSION, formula
+	return b
+}
+
+// EscalationPeriod sets default duration of escalation step, at least a minute.
+func (b *ActionBuilder) EscalationPeriod(d time.Duration) *ActionBuilder {
+	if d < time.Minute {
+		return b.fail(fmt.Errorf("Escalation period should be at least a minute, got %s.", d))
+	}
+	b.a.EscPeriod = formatDuration(int64(d / time.Second))
+	return b
+}
+
+// Steps sets escalation steps of next problem operations, to is 0 for infinite escalation.
+func (b *ActionBuilder) Steps(from, to int) *ActionBuilder {
+	if from < 1 || (to != 0 && to < from) {
+		return b.fail(fmt.Errorf("Unexpected escalation steps %d-%d.", from, to))
+	}
+	b.from, b.to = from, to
+	return b
+}
+
+// After sets escalation step of next problem operations to one starting after given duration of problem.
+// Duration should be a multiple of EscalationPeriod, or of default one hour if it is not set.
+func (b *ActionBuilder) After(d time.Duration) *ActionBuilder {
+	escPeriod := b.a.EscPeriod
+	if escPeriod == "" {
+		escPeriod = "1h"
+	}
+	period, err := parseDuration(escPeriod)
+	if err != nil || period == 0 || d%period != 0 {
+		return b.fail(fmt.Errorf("Duration %s is not a multiple of escalation period %q.", d, escPeriod))
+	}
+	step := int(d/period) + 1
+	return b.Steps(step, step)
+}
+
+// OnProblem makes next operations problem operations.
+func (b *ActionBuilder) OnProblem() *ActionBuilder {
+	b.mode = &b.a.Operations
+	return b
+}
+
+// OnRecovery makes next operations recovery operations (Zabbix 3.2+).
+func (b *ActionBuilder) OnRecovery() *ActionBuilder {
+	b.mode = &b.a.RecoveryOperations
+	return b
+}
+
+// OnUpdate makes next operations update (acknowledge) operations (Zabbix 3.4+).
+func (b *ActionBuilder) OnUpdate() *ActionBuilder {
+	b.mode = &b.a.UpdateOperations
+	return b
+}
+
+// Operation adds operation. Problem operations of trigger and internal actions without escalation steps
+// get ones set by Steps or After.
+func (b *ActionBuilder) Operation(op Operation) *ActionBuilder {
+	escalated := b.a.EventSource == SourceTrigger || b.a.EventSource == SourceInternal
+	if b.mode == &b.a.Operations && escalated && op.EscStepFrom == 0 && op.EscStepTo == 0 {
+		op.EscStepFrom, op.EscStepTo = b.from, b.to
+	}
+	*b.mode = append(*b.mode, op)
+	return b
+}
+
+// SendToUserGroups adds operation sending default message to users of groups via media type,
+// or via all media types if mediaTypeId is empty.
+func (b *ActionBuilder) SendToUserGroups(mediaTypeId string, ids ...string) *ActionBuilder {
+	op := Operation{OperationType: SEND_MESSAGE, OpMessage: &OpMessage{DefaultMsg: 1, MediaTypeId: mediaTypeId}}
+	for _, id := range ids {
+		op.OpMessageGrp = append(op.OpMessageGrp, OpMessageGrp{UsrGrpId: id})
+	}
+	return b.Operation(op)
+}
+
+// SendToUsers adds operation sending default message to users via media type,
+// or via all media types if mediaTypeId is empty.
+func (b *ActionBuilder) SendToUsers(mediaTypeId string, ids ...string) *ActionBuilder {
+	op := Operation{OperationType: SEND_MESSAGE, OpMessage: &OpMessage{DefaultMsg: 1, MediaTypeId: mediaTypeId}}
+	for _, id := range ids {
+		op.OpMessageUsr = append(op.OpMessageUsr, OpMessageUsr{UserId: id})
+	}
+	return b.Operation(op)
+}
+
+// NotifyAllInvolved adds recovery or update operation notifying everyone who received messages about the problem.
+func (b *ActionBuilder) NotifyAllInvolved() *ActionBuilder {
+	switch b.mode {
+	case &b.a.RecoveryOperations:
+		return b.Operation(Operation{OperationType: NOTIFY_ALL_INVOLVED, OpMessage: &OpMessage{DefaultMsg: 1}})
+	case &b.a.UpdateOperations:
+		return b.Operation(Operation{OperationType: NOTIFY_UPDATE_INVOLVED, OpMessage: &OpMessage{DefaultMsg: 1}})
+	}
+	return b.fail(fmt.Errorf("Notification of all involved is not a problem operation."))
+}
+
+// RunScript adds operation executing global script on hosts, or on host of event if no hosts are given.
+func (b *ActionBuilder) RunScript(scriptId string, hostIds ...string) *ActionBuilder {
+	if len(hostIds) == 0 {
+		hostIds = []string{"0"}
+	}
+	op := Operation{OperationType: REMOTE_COMMAND, OpCommand: &OpCommand{Type: GLOBAL_SCRIPT, ScriptId: scriptId}}
+	for _, id := range hostIds {
+		op.OpCommandHst = append(op.OpCommandHst, OpCommandHst{HostId: id})
+	}
+	return b.Operation(op)
+}
+
+// RunScriptOnGroups adds operation executing global script on hosts of groups.
+func (b *ActionBuilder) RunScriptOnGroups(scriptId string, groupIds ...string) *ActionBuilder {
+	op := Operation{OperationType: REMOTE_COMMAND, OpCommand: &OpCommand{Type: GLOBAL_SCRIPT, ScriptId: scriptId}}
+	for _, id := range groupIds {
+		op.OpCommandGrp = append(op.OpCommandGrp, OpCommandGrp{GroupId: id})
+	}
+	return b.Operation(op)
+}
+
+// AddHost adds operation adding discovered or autoregistered host.
+func (b *ActionBuilder) AddHost() *ActionBuilder {
+	return b.Operation(Operation{OperationType: ADD_HOST})
+}
+
+// RemoveHost adds operation removing discovered host.
+func (b *ActionBuilder) RemoveHost() *ActionBuilder {
+	return b.Operation(Operation{OperationType: REMOVE_HOST})
+}
+
+// EnableHost adds operation enabling host.
+func (b *ActionBuilder) EnableHost() *ActionBuilder {
+	return b.Operation(Operation{OperationType: ENABLE_HOST})
+}
+
+// DisableHost adds operation disabling host.
+func (b *ActionBuilder) DisableHost() *ActionBuilder {
+	return b.Operation(Operation{OperationType: DISABLE_HOST})
+}
+
+// AddToHostGroups adds operation adding host to groups.
+func (b *ActionBuilder) AddToHostGroups(ids ...string) *ActionBuilder {
+	return b.Operation(Operation{OperationType: ADD_TO_HOST_GROUP, OpGroup: opGroups(ids)})
+}
+
+// RemoveFromHostGroups adds operation removing host from groups.
+func (b *ActionBuilder) RemoveFromHostGroups(ids ...string) *ActionBuilder {
+	return b.Operation(Operation{OperationType: REMOVE_FROM_HOST_GROUP, OpGroup: opGroups(ids)})
+}
+
+// LinkTemplates adds operation linking templates to host.
+func (b *ActionBuilder) LinkTemplates(ids ...string) *ActionBuilder {
+	return b.Operation(Operation{OperationType: LINK_TO_TEMPLATE, OpTemplate: opTemplates(ids)})
+}
+
+// UnlinkTemplates adds operation unlinking templates from host.
+func (b *ActionBuilder) UnlinkTemplates(ids ...string) *ActionBuilder {
+	return b.Operation(Operation{OperationType: UNLINK_FROM_TEMPLATE, OpTemplate: opTemplates(ids)})
+}
+
+func opGroups(ids []string) (res OpGroups) {
+	for _, id := range ids {
+		res = append(res, OpGroup{GroupId: id})
+	}
+	return
+}
+
+func opTemplates(ids []string) (res OpTemplates) {
+	for _, id := range ids {
+		res = append(res, OpTemplate{TemplateId: id})
+	}
+	return
+}
+
+// Build returns validated action or first error.
+// With Formula conditions without formula IDs get ones by their order: "A", "B", ..., "Z", "AA", "AB", ...
+func (b *ActionBuilder) Build() (res Action, err error) {
+	if b.err != nil {
+		return res, b.err
+	}
+	a := b.a
+	if b.a.Filter != nil {
+		f := *b.a.Filter
+		f.Conditions = append(Conditions(nil), f.Conditions...)
+		if f.EvalType == CUSTOM_EXPRESSION {
+			for i := range f.Conditions {
+				if f.Conditions[i].FormulaId == "" {
+					f.Conditions[i].FormulaId = formulaId(i)
+				}
+			}
+		}
+		a.Filter = &f
+	}
+	if err = a.Validate(); err != nil {
+		return
+	}
+	return a, nil
+}
+
+// Returns formula ID of i-th condition.
+func formulaId(i int) (res string) {
+	for ; i >= 0; i = i/26 - 1 {
+		res = string(rune('A'+i%26)) + res
+	}
+	return
+}
+
+// Create builds action and creates it. Update operations are sent as acknowledge operations before Zabbix 5.2.
+func (b *ActionBuilder) Create(api *API) (res Action, err error) {
+	a, err := b.Build()
+	if err != nil {
+		return
+	}
+	if len(a.UpdateOperations) > 0 {
+		var ok bool
+		if ok, err = api.versionAtLeast(5, 2); err != nil {
+			return
+		}
+		if !ok {
+			a.AcknowledgeOperations, a.UpdateOperations = a.UpdateOperations, nil
+		}
+	}
+	actions := Actions{a}
+	if err = api.ActionsCreate(actions); err != nil {
+		return
+	}
+	return actions[0], nil
+}
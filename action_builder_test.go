@@ -0,0 +1,68 @@
+package zabbix_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	. "."
+)
+
+func TestActionBuilder(t *testing.T) {
+	api, requests := fakeAPI(t, map[string]string{
+		"APIInfo.version": `"5.0.0"`,
+		"action.create":   `{"actionids": ["3"]}`,
+	})
+
+	a, err := NewActionBuilder("Database problems", SourceTrigger).
+		Severity(OperatorGreaterOrEqual, High).
+		HostGroups("2").
+		Tag("service", OperatorLike, "db").
+		Formula("A and (B or C)").
+		EscalationPeriod(5*time.Minute).
+		SendToUserGroups("1", "7").
+		After(10*time.Minute).RunScript("4", "10084").
+		OnRecovery().NotifyAllInvolved().
+		OnUpdate().SendToUsers("", "5").
+		Create(api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.ActionId != "3" || a.EscPeriod != "5m" || a.Filter.Conditions[2].FormulaId != "C" || a.Filter.Conditions[2].Value2 != "service" {
+		t.Errorf("Unexpected action %#v", a)
+	}
+	ops := a.Operations
+	if ops[0].EscStepFrom != 1 || ops[0].EscStepTo != 1 || ops[0].OpMessageGrp[0].UsrGrpId != "7" ||
+		ops[1].EscStepFrom != 3 || ops[1].EscStepTo != 3 || ops[1].OpCommand.Type != GLOBAL_SCRIPT || ops[1].OpCommandHst[0].HostId != "10084" {
+		t.Errorf("Unexpected operations %#v", ops)
+	}
+	if a.RecoveryOperations[0].OperationType != NOTIFY_ALL_INVOLVED || a.RecoveryOperations[0].EscStepFrom != 0 {
+		t.Errorf("Unexpected recovery operations %#v", a.RecoveryOperations)
+	}
+	params := (*requests)[1]["params"].([]interface{})[0].(map[string]interface{})
+	if params["update_operations"] != nil || len(params["acknowledge_operations"].([]interface{})) != 1 {
+		t.Errorf("Unexpected params %v", params)
+	}
+
+	for expected, b := range map[string]*ActionBuilder{
+		"not allowed for event source": NewActionBuilder("a", SourceTrigger).AddHost(),
+		"no user groups and users":     NewActionBuilder("a", SourceTrigger).SendToUserGroups("1"),
+		"global script is not set":     NewActionBuilder("a", SourceTrigger).RunScript(""),
+		"no host groups":               NewActionBuilder("a", SourceDiscoveryRule).AddToHostGroups(),
+		"condition 1 of type 4":        NewActionBuilder("a", SourceActiveAgent).Severity(OperatorEqual, High).AddHost(),
+		"not a multiple":               NewActionBuilder("a", SourceTrigger).EscalationPeriod(time.Hour).After(30 * time.Minute),
+		"not a problem operation":      NewActionBuilder("a", SourceTrigger).NotifyAllInvolved(),
+		"has no operations":            NewActionBuilder("a", SourceTrigger),
+		"message is not set":           NewActionBuilder("a", SourceTrigger).Operation(Operation{OperationType: SEND_MESSAGE, OpMessageGrp: OpMessageGrps{{UsrGrpId: "7"}}}),
+		"escalation period \"1h\"":     NewActionBuilder("a", SourceTrigger).After(30 * time.Minute),
+	} {
+		if _, err := b.Build(); err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected error with %q, got %v", expected, err)
+		}
+	}
+
+	a, err = NewActionBuilder("a", SourceTrigger).After(2*time.Hour).SendToUsers("", "1").Build()
+	if err != nil || a.EscPeriod != "" || a.Operations[0].EscStepFrom != 3 || a.Operations[0].EscStepTo != 3 {
+		t.Errorf("Unexpected action %#v, error %v", a, err)
+	}
+}
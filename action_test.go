@@ -57,3 +57,53 @@ func TestActions(t *testing.T) {
 
 	//DeleteAction(action2, t)
 }
+
+func TestActionModel(t *testing.T) {
+	api, requests := fakeAPI(t, map[string]string{
+		"APIInfo.version": `"5.2.0"`,
+		"action.get": `[{"actionid": "3", "name": "Notify", "eventsource": "0", "status": "0", "esc_period": "1h",
+			"filter": {"evaltype": "3", "formula": "A and B", "eval_formula": "A and B", "conditions": [
+				{"conditionid": "5", "conditiontype": "4", "operator": "5", "value": "4", "value2": "", "formulaid": "A"},
+				{"conditionid": "6", "conditiontype": "0", "operator": "0", "value": "2", "value2": "", "formulaid": "B"}]},
+			"operations": [{"operationid": "7", "actionid": "3", "operationtype": "1", "esc_step_from": "3", "esc_step_to": "3", "evaltype": "0",
+				"opcommand": {"operationid": "7", "type": "4", "scriptid": "1"}, "opcommand_hst": [{"opcommand_hstid": "1", "operationid": "7", "hostid": "10084"}],
+				"opconditions": [{"opconditionid": "2", "conditiontype": "14", "operator": "0", "value": "0", "operationid": "7"}]}],
+			"recovery_operations": [{"operationid": "8", "operationtype": "11", "opmessage": {"default_msg": "1", "mediatypeid": "0"}}],
+			"update_operations": [{"operationid": "9", "operationtype": "0", "opmessage_grp": [{"operationid": "9", "usrgrpid": "7"}]}]}]`,
+		"action.update": `{"actionids": ["3"]}`,
+	})
+
+	action, err := api.ActionGetById("3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	params := (*requests)[1]["params"].(map[string]interface{})
+	if params["selectFilter"] != "extend" || params["selectRecoveryOperations"] != "extend" ||
+		params["selectUpdateOperations"] != "extend" || params["selectAcknowledgeOperations"] != nil {
+		t.Errorf("Unexpected params %v", params)
+	}
+	f := action.Filter
+	if f == nil || f.EvalType != CUSTOM_EXPRESSION || len(f.Conditions) != 2 ||
+		f.Conditions[0].ConditionType != ConditionTriggerSeverity || f.Conditions[0].Operator != OperatorGreaterOrEqual {
+		t.Fatalf("Unexpected filter %#v", f)
+	}
+	op := action.Operations[0]
+	if op.OperationType != REMOTE_COMMAND || op.EscStepFrom != 3 || op.OpCommand.Type != GLOBAL_SCRIPT ||
+		op.OpCommandHst[0].HostId != "10084" || op.OpConditions[0].OpConditionType != ConditionEventAcknowledged {
+		t.Errorf("Unexpected operation %#v", op)
+	}
+	if action.RecoveryOperations[0].OperationType != NOTIFY_ALL_INVOLVED || action.RecoveryOperations[0].OpMessage.DefaultMsg != 1 ||
+		action.UpdateOperations[0].OpMessageGrp[0].UsrGrpId != "7" {
+		t.Errorf("Unexpected action %#v", action)
+	}
+
+	action.Status = 1
+	if err = api.ActionsUpdate(Actions{*action}); err != nil {
+		t.Fatal(err)
+	}
+	params = (*requests)[2]["params"].([]interface{})[0].(map[string]interface{})
+	filter := params["filter"].(map[string]interface{})
+	if _, present := params["eventsource"]; present || filter["eval_formula"] != nil || params["status"] != 1.0 {
+		t.Errorf("Unexpected params %v", params)
+	}
+}
@@ -0,0 +1,103 @@
+package zabbix
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+const agentNotSupported = "ZBX_NOTSUPPORTED"
+
+// NotSupportedError is returned by AgentClient when agent replies with ZBX_NOTSUPPORTED.
+type NotSupportedError struct {
+	Key    string
+	Reason string // empty for agents before Zabbix 2.2
+}
+
+func (e *NotSupportedError) Error() string {
+	if e.Reason == "" {
+		return fmt.Sprintf("Item %s is not supported.", e.Key)
+	}
+	return fmt.Sprintf("Item %s is not supported: %s", e.Key, e.Reason)
+}
+
+// AgentClient queries Zabbix agent passive checks like zabbix_get does.
+// See https://www.zabbix.com/documentation/4.0/manual/appendix/items/activepassive
+type AgentClient struct {
+	Addr    string        // agent address, host:port
+	Timeout time.Duration // connection and I/O timeout for each query
+
+	// Dial is used to open connections if not nil.
+	Dial func(network, addr string) (net.Conn, error)
+}
+
+// NewAgentClient creates new AgentClient with zabbix_get defaults.
+// Default port 10050 is used if addr doesn't contain one.
+func NewAgentClient(addr string) *AgentClient {
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, "10050")
+	}
+	return &AgentClient{Addr: addr, Timeout: 30 * time.Second}
+}
+
+// NewAgentClientForInterface creates new AgentClient for host interface of Agent type.
+// IP or DNS is used depending on UseIP field.
+func NewAgentClientForInterface(iface HostInterface) (c *AgentClient, err error) {
+	if iface.Type != Agent {
+		err = fmt.Errorf("Expected interface of Agent type, got %d.", iface.Type)
+		return
+	}
+
+	host := iface.DNS
+	if iface.UseIP == 1 {
+		host = iface.IP
+	}
+	if host == "" {
+		err = fmt.Errorf("Interface has no address: %#v", iface)
+		return
+	}
+
+	port := iface.Port
+	if port == "" {
+		port = "10050"
+	}
+	c = NewAgentClient(net.JoinHostPort(host, port))
+	return
+}
+
+// Get requests value of item key from agent.
+// Returns *NotSupportedError if agent doesn't support key.
+func (c *AgentClient) Get(key string) (value string, err error) {
+	var conn net.Conn
+	if c.Dial != nil {
+		conn, err = c.Dial("tcp", c.Addr)
+	} else {
+		conn, err = net.DialTimeout("tcp", c.Addr, c.Timeout)
+	}
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	if c.Timeout > 0 {
+		conn.SetDeadline(time.Now().Add(c.Timeout))
+	}
+
+	if err = WritePacket(conn, []byte(key+"\n"), false); err != nil {
+		return
+	}
+	b, err := ReadPacket(conn)
+	if err != nil {
+		return
+	}
+
+	// since Zabbix 2.2 reason follows after NUL
+	if bytes.HasPrefix(b, []byte(agentNotSupported)) {
+		reason := strings.TrimPrefix(string(b[len(agentNotSupported):]), "\x00")
+		err = &NotSupportedError{Key: key, Reason: reason}
+		return
+	}
+	value = strings.TrimRight(string(b), "\n")
+	return
+}
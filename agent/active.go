@@ -0,0 +1,266 @@
+package agent
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/seuf/zabbix"
+)
+
+// ActiveCheck is an item key which server asks active agent to check.
+type ActiveCheck struct {
+	Key         string
+	Delay       time.Duration
+	LastLogSize int64
+	MTime       int64
+}
+
+type activeCheck struct {
+	Key         string      `json:"key"`
+	Delay       interface{} `json:"delay"` // number of seconds or, since Zabbix 4.0, string like "30s"
+	LastLogSize int64       `json:"lastlogsize"`
+	MTime       int64       `json:"mtime"`
+}
+
+// Parses item delay. Only the update interval is used, flexible and scheduling intervals are ignored.
+func parseDelay(delay interface{}) (d time.Duration, err error) {
+	switch delay := delay.(type) {
+	case float64:
+		d = time.Duration(delay) * time.Second
+	case string:
+		s := strings.SplitN(delay, ";", 2)[0]
+		unit := time.Second
+		if s != "" {
+			switch s[len(s)-1] {
+			case 's':
+				s = s[:len(s)-1]
+			case 'm':
+				s, unit = s[:len(s)-1], time.Minute
+			case 'h':
+				s, unit = s[:len(s)-1], time.Hour
+			case 'd':
+				s, unit = s[:len(s)-1], 24*time.Hour
+			case 'w':
+				s, unit = s[:len(s)-1], 7*24*time.Hour
+			}
+		}
+		var n int
+		n, err = strconv.Atoi(s)
+		d = time.Duration(n) * unit
+	default:
+		err = fmt.Errorf("unexpected delay %#v", delay)
+	}
+	return
+}
+
+type activeValue struct {
+	Host  string `json:"host"`
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	State int    `json:"state,omitempty"` // 1 - not supported
+	Id    int64  `json:"id"`
+	Clock int64  `json:"clock"`
+	Ns    int    `json:"ns"`
+}
+
+// ActiveAgent performs active checks: it requests list of checks from server, collects values
+// with Registry and pushes them back.
+// See https://www.zabbix.com/documentation/4.0/manual/appendix/items/activepassive#active_checks
+type ActiveAgent struct {
+	ServerAddr      string        // server or proxy address, host:port
+	Host            string        // host name as configured in Zabbix
+	HostMetadata    string        // optional metadata for active agent auto-registration
+	Registry        *Registry     // item key handlers
+	RefreshInterval time.Duration // how often list of checks is refreshed
+	Timeout         time.Duration // connection and I/O timeout for each request
+	Logger          *log.Logger   // errors logger, nil by default
+
+	session string
+	lastId  int64
+}
+
+// NewActiveAgent creates new ActiveAgent with zabbix_agentd defaults.
+// Default port 10051 is used if serverAddr doesn't contain one.
+func NewActiveAgent(serverAddr, host string, registry *Registry) *ActiveAgent {
+	if _, _, err := net.SplitHostPort(serverAddr); err != nil {
+		serverAddr = net.JoinHostPort(serverAddr, "10051")
+	}
+	return &ActiveAgent{
+		ServerAddr:      serverAddr,
+		Host:            host,
+		Registry:        registry,
+		RefreshInterval: 2 * time.Minute,
+		Timeout:         3 * time.Second,
+	}
+}
+
+func (a *ActiveAgent) printf(format string, v ...interface{}) {
+	if a.Logger != nil {
+		a.Logger.Printf(format, v...)
+	}
+}
+
+// Sends JSON request to server and decodes JSON response.
+func (a *ActiveAgent) exchange(req interface{}, res interface{}) (err error) {
+	b, err := json.Marshal(req)
+	if err != nil {
+		return
+	}
+
+	conn, err := net.DialTimeout("tcp", a.ServerAddr, a.Timeout)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	if a.Timeout > 0 {
+		conn.SetDeadline(time.Now().Add(a.Timeout))
+	}
+
+	if err = zabbix.WritePacket(conn, b, false); err != nil {
+		return
+	}
+	b, err = zabbix.ReadPacket(conn)
+	if err != nil {
+		return
+	}
+	return json.Unmarshal(b, res)
+}
+
+// ActiveChecks requests list of active checks for Host from server.
+func (a *ActiveAgent) ActiveChecks() (res []ActiveCheck, err error) {
+	req := map[string]string{"request": "active checks", "host": a.Host}
+	if a.HostMetadata != "" {
+		req["host_metadata"] = a.HostMetadata
+	}
+
+	var response struct {
+		Response string        `json:"response"`
+		Info     string        `json:"info"`
+		Data     []activeCheck `json:"data"`
+	}
+	if err = a.exchange(req, &response); err != nil {
+		return
+	}
+	if response.Response != "success" {
+		err = zabbix.ProtocolError(fmt.Sprintf("active checks response %q: %s", response.Response, response.Info))
+		return
+	}
+
+	res = make([]ActiveCheck, len(response.Data))
+	for i, c := range response.Data {
+		res[i] = ActiveCheck{Key: c.Key, LastLogSize: c.LastLogSize, MTime: c.MTime}
+		if res[i].Delay, err = parseDelay(c.Delay); err != nil {
+			err = fmt.Errorf("Bad delay for %s: %s", c.Key, err)
+			return
+		}
+	}
+	return
+}
+
+// Pushes collected values to server.
+func (a *ActiveAgent) send(values []activeValue) (err error) {
+	now := time.Now()
+	req := struct {
+		Request string        `json:"request"`
+		Session string        `json:"session"`
+		Data    []activeValue `json:"data"`
+		Clock   int64         `json:"clock"`
+		Ns      int           `json:"ns"`
+	}{"agent data", a.session, values, now.Unix(), now.Nanosecond()}
+
+	var response struct {
+		Response string `json:"response"`
+		Info     string `json:"info"`
+	}
+	if err = a.exchange(req, &response); err != nil {
+		return
+	}
+	if response.Response != "success" {
+		err = zabbix.ProtocolError(fmt.Sprintf("agent data response %q: %s", response.Response, response.Info))
+	}
+	return
+}
+
+// Collects value of active check.
+func (a *ActiveAgent) collect(key string, now time.Time) activeValue {
+	a.lastId++
+	v := activeValue{Host: a.Host, Key: key, Id: a.lastId, Clock: now.Unix(), Ns: now.Nanosecond()}
+	value, err := a.Registry.Get(key)
+	if err != nil {
+		v.State = 1
+		value = notSupportedReason(err)
+	}
+	v.Value = value
+	return v
+}
+
+// maxBufferedValues limits values kept while server is unavailable, oldest are dropped.
+const maxBufferedValues = 1000
+
+// Run performs active checks until ctx is done, then returns ctx.Err().
+// Errors of communication with server are logged and retried.
+func (a *ActiveAgent) Run(ctx context.Context) error {
+	b := make([]byte, 16)
+	rand.Read(b)
+	a.session = hex.EncodeToString(b)
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	next := make(map[string]time.Time) // next check time by key
+	var checks []ActiveCheck
+	var nextRefresh time.Time
+	var buffer []activeValue
+
+	for {
+		now := time.Now()
+
+		if !now.Before(nextRefresh) {
+			list, err := a.ActiveChecks()
+			if err == nil {
+				checks = list
+				n := make(map[string]time.Time, len(checks))
+				for _, c := range checks {
+					n[c.Key] = next[c.Key]
+				}
+				next = n
+			} else {
+				a.printf("Failed to refresh active checks: %s", err)
+			}
+			nextRefresh = now.Add(a.RefreshInterval)
+		}
+
+		for _, c := range checks {
+			if now.Before(next[c.Key]) {
+				continue
+			}
+			buffer = append(buffer, a.collect(c.Key, now))
+			next[c.Key] = now.Add(c.Delay)
+		}
+
+		if len(buffer) > 0 {
+			if err := a.send(buffer); err == nil {
+				buffer = nil
+			} else {
+				a.printf("Failed to send values: %s", err)
+				if len(buffer) > maxBufferedValues {
+					buffer = buffer[len(buffer)-maxBufferedValues:]
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
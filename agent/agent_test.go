@@ -0,0 +1,177 @@
+package agent_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	. "."
+	"github.com/seuf/zabbix"
+)
+
+func TestParseKey(t *testing.T) {
+	for key, expected := range map[string][]string{
+		`agent.ping`:                            nil,
+		`agent.ping[]`:                          {""},
+		`vfs.fs.size[/,free]`:                   {"/", "free"},
+		`log[/var/log/syslog,"a, \"b\"",,skip]`: {"/var/log/syslog", `a, "b"`, "", "skip"},
+		`net.tcp.service[ tcp, , 80]`:           {"tcp", "", "80"},
+		`system.run[[a,b],c]`:                   {"a,b", "c"},
+	} {
+		_, params, err := ParseKey(key)
+		if err != nil {
+			t.Errorf("%s: %s", key, err)
+			continue
+		}
+		if !reflect.DeepEqual(expected, params) {
+			t.Errorf("%s: expected %#v, got %#v", key, expected, params)
+		}
+	}
+
+	for _, key := range []string{``, `[a]`, `key[a`, `key["a]`, `key["a"b]`, `key a`} {
+		if _, _, err := ParseKey(key); err == nil {
+			t.Errorf("%s: expected error", key)
+		}
+	}
+}
+
+func newRegistry() *Registry {
+	r := NewRegistry()
+	r.Handle("agent.ping", func(params []string) (string, error) { return "1", nil })
+	r.Handle("echo", func(params []string) (string, error) { return strings.Join(params, " "), nil })
+	r.Handle("fail", func(params []string) (string, error) { return "", errors.New("Failed.") })
+	return r
+}
+
+func TestServer(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	go NewServer(newRegistry()).Serve(l)
+
+	client := zabbix.NewAgentClient(l.Addr().String())
+	value, err := client.Get(`echo[a,"b c"]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value != "a b c" {
+		t.Errorf("Bad value: %q", value)
+	}
+
+	for key, reason := range map[string]string{"fail": "Failed.", "unknown": "Unsupported item key."} {
+		_, err = client.Get(key)
+		if e, ok := err.(*zabbix.NotSupportedError); !ok || e.Reason != reason {
+			t.Errorf("%s: expected NotSupportedError, got %#v", key, err)
+		}
+	}
+
+	// plain-text request without header
+	conn, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	conn.Write([]byte("agent.ping\n"))
+	b, err := zabbix.ReadPacket(conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "1" {
+		t.Errorf("Bad value: %q", b)
+	}
+
+	for request, response := range map[string]string{
+		"agent.ping": "1", // plain-text without newline
+		"ZBXD\x01\x10\x00\x00\x00\x00\x00\x00\x00age": "", // truncated packet is not answered
+		"ZBXD\x01\x10": "",
+	} {
+		conn, err := net.Dial("tcp", l.Addr().String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		conn.Write([]byte(request))
+		conn.(*net.TCPConn).CloseWrite()
+		b, err := zabbix.ReadPacket(conn)
+		conn.Close()
+		if response == "" && err == nil {
+			t.Errorf("%q: unexpected response %q", request, b)
+		}
+		if response != "" && string(b) != response {
+			t.Errorf("%q: expected %q, got %q (%v)", request, response, b, err)
+		}
+	}
+}
+
+func TestActiveAgent(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	data := make(chan []interface{}, 10)
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			b, _ := zabbix.ReadPacket(conn)
+			var req map[string]interface{}
+			json.Unmarshal(b, &req)
+
+			var res interface{}
+			switch req["request"] {
+			case "active checks":
+				res = map[string]interface{}{"response": "success", "data": []interface{}{
+					map[string]interface{}{"key": "agent.ping", "delay": 30, "lastlogsize": 0, "mtime": 0},
+					map[string]interface{}{"key": "fail", "delay": "1m", "lastlogsize": 0, "mtime": 0},
+				}}
+			case "agent data":
+				data <- req["data"].([]interface{})
+				res = map[string]string{"response": "success", "info": "processed: 2; failed: 0; total: 2; seconds spent: 0.000100"}
+			}
+			b, _ = json.Marshal(res)
+			zabbix.WritePacket(conn, b, false)
+			conn.Close()
+		}
+	}()
+
+	a := NewActiveAgent(l.Addr().String(), "test host", newRegistry())
+	checks, err := a.ActiveChecks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []ActiveCheck{{Key: "agent.ping", Delay: 30 * time.Second}, {Key: "fail", Delay: time.Minute}}
+	if !reflect.DeepEqual(expected, checks) {
+		t.Errorf("Checks are not equal:\n%#v\n%#v", expected, checks)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- a.Run(ctx) }()
+
+	values := <-data
+	cancel()
+	if err = <-done; err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+
+	if len(values) != 2 {
+		t.Fatalf("Bad values: %#v", values)
+	}
+	v0, v1 := values[0].(map[string]interface{}), values[1].(map[string]interface{})
+	if v0["host"] != "test host" || v0["key"] != "agent.ping" || v0["value"] != "1" || v0["state"] != nil {
+		t.Errorf("Bad value: %#v", v0)
+	}
+	if v1["key"] != "fail" || v1["value"] != "Failed." || v1["state"] != 1.0 {
+		t.Errorf("Bad value: %#v", v1)
+	}
+}
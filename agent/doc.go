@@ -0,0 +1,6 @@
+// Package agent implements Zabbix agent protocols, so ZabbixAgent and ZabbixAgentActive items
+// can be served from Go code without installing C agent.
+//
+// Register item key handlers in Registry, then serve them with Server (passive checks)
+// and/or ActiveAgent (active checks).
+package agent
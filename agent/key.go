@@ -0,0 +1,102 @@
+package agent
+
+import (
+	"fmt"
+	"strings"
+)
+
+// KeyError is returned by ParseKey for malformed item keys.
+type KeyError struct {
+	Key    string
+	Reason string
+}
+
+func (e *KeyError) Error() string {
+	return fmt.Sprintf("Invalid item key %q: %s.", e.Key, e.Reason)
+}
+
+func isKeyChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '.' || c == '_' || c == '-'
+}
+
+// ParseKey splits item key like `vfs.fs.size[/,free]` into name and parameters
+// according to https://www.zabbix.com/documentation/4.0/manual/config/items/item/key
+// Quoted parameters are unquoted, array parameters like `[a,b]` are returned as is without brackets.
+// Key without brackets has nil parameters, key with empty brackets has one empty parameter.
+func ParseKey(s string) (name string, params []string, err error) {
+	i := 0
+	for i < len(s) && isKeyChar(s[i]) {
+		i++
+	}
+	name = s[:i]
+	if name == "" {
+		err = &KeyError{s, "empty key name"}
+		return
+	}
+	if i == len(s) {
+		return
+	}
+	if s[i] != '[' || s[len(s)-1] != ']' {
+		err = &KeyError{s, fmt.Sprintf("unexpected character %q", s[i])}
+		return
+	}
+
+	params, err = parseParams(s[i+1 : len(s)-1])
+	if err != nil {
+		err = &KeyError{s, err.Error()}
+	}
+	return
+}
+
+func parseParams(s string) (params []string, err error) {
+	for {
+		s = strings.TrimLeft(s, " ")
+		var param string
+		switch {
+		case strings.HasPrefix(s, `"`):
+			var b []byte
+			i := 1
+			for ; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' && i+1 < len(s) && s[i+1] == '"' {
+					i++
+				}
+				b = append(b, s[i])
+			}
+			if i == len(s) {
+				return nil, fmt.Errorf("unterminated quoted parameter")
+			}
+			param = string(b)
+			s = strings.TrimLeft(s[i+1:], " ")
+			if s != "" && s[0] != ',' {
+				return nil, fmt.Errorf("unexpected character %q after quoted parameter", s[0])
+			}
+
+		case strings.HasPrefix(s, "["):
+			i := strings.IndexByte(s, ']')
+			if i == -1 {
+				return nil, fmt.Errorf("unterminated array parameter")
+			}
+			param = s[1:i]
+			s = strings.TrimLeft(s[i+1:], " ")
+			if s != "" && s[0] != ',' {
+				return nil, fmt.Errorf("unexpected character %q after array parameter", s[0])
+			}
+
+		default:
+			i := strings.IndexAny(s, ",]")
+			if i == -1 {
+				i = len(s)
+			} else if s[i] == ']' {
+				return nil, fmt.Errorf("unexpected character ']'")
+			}
+			param = s[:i]
+			s = s[i:]
+		}
+
+		params = append(params, param)
+		if s == "" {
+			return
+		}
+		s = s[1:] // skip comma
+	}
+}
@@ -0,0 +1,93 @@
+package agent
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"log"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/seuf/zabbix"
+)
+
+// Server serves passive checks like zabbix_agentd listener does.
+// See https://www.zabbix.com/documentation/4.0/manual/appendix/items/activepassive#passive_checks
+type Server struct {
+	Registry *Registry
+	Timeout  time.Duration // I/O timeout for each connection
+	Logger   *log.Logger   // connection errors logger, nil by default
+}
+
+// NewServer creates new Server with zabbix_agentd defaults.
+func NewServer(registry *Registry) *Server {
+	return &Server{Registry: registry, Timeout: 3 * time.Second}
+}
+
+// ListenAndServe listens on TCP address (for example, ":10050") and calls Serve.
+func ListenAndServe(addr string, registry *Registry) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return NewServer(registry).Serve(l)
+}
+
+func (s *Server) printf(format string, v ...interface{}) {
+	if s.Logger != nil {
+		s.Logger.Printf(format, v...)
+	}
+}
+
+// Serve accepts connections on l and serves each in a new goroutine.
+// It returns when l.Accept fails, for example, after l is closed.
+func (s *Server) Serve(l net.Listener) error {
+	defer l.Close()
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if e, ok := err.(net.Error); ok && e.Temporary() {
+				s.printf("Accept error: %s", err)
+				time.Sleep(100 * time.Millisecond)
+				continue
+			}
+			return err
+		}
+		go s.serveConn(conn)
+	}
+}
+
+func (s *Server) serveConn(conn net.Conn) {
+	defer conn.Close()
+	if s.Timeout > 0 {
+		conn.SetDeadline(time.Now().Add(s.Timeout))
+	}
+
+	// requests without header are still accepted for old servers and plain-text tools
+	var key string
+	r := bufio.NewReader(conn)
+	b, err := r.Peek(4)
+	if err == nil && bytes.Equal(b, []byte("ZBXD")) {
+		b, err = zabbix.ReadPacket(r)
+		key = string(b)
+	} else {
+		key, err = r.ReadString('\n')
+		if err == io.EOF && key != "" { // key without newline
+			err = nil
+		}
+	}
+	if err != nil {
+		s.printf("Read error from %s: %s", conn.RemoteAddr(), err)
+		return
+	}
+	key = strings.TrimRight(key, "\r\n")
+
+	value, err := s.Registry.Get(key)
+	if err != nil {
+		value = "ZBX_NOTSUPPORTED\x00" + notSupportedReason(err)
+	}
+	if err = zabbix.WritePacket(conn, []byte(value), false); err != nil {
+		s.printf("Write error to %s: %s", conn.RemoteAddr(), err)
+	}
+}
@@ -0,0 +1,76 @@
+package agent
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/seuf/zabbix"
+)
+
+// HandlerFunc returns value of item key for given parameters.
+// Returned error makes item not supported with error text as a reason.
+type HandlerFunc func(params []string) (value string, err error)
+
+// Registry maps item key names to handlers. It is safe for concurrent use.
+type Registry struct {
+	mu       sync.RWMutex
+	handlers map[string]HandlerFunc
+}
+
+// NewRegistry creates empty registry.
+func NewRegistry() *Registry {
+	return &Registry{handlers: make(map[string]HandlerFunc)}
+}
+
+// Handle registers handler for item key name (without parameters), replacing existing one.
+func (r *Registry) Handle(name string, handler HandlerFunc) {
+	r.mu.Lock()
+	r.handlers[name] = handler
+	r.mu.Unlock()
+}
+
+// Keys returns sorted registered key names.
+func (r *Registry) Keys() (res []string) {
+	r.mu.RLock()
+	for name := range r.handlers {
+		res = append(res, name)
+	}
+	r.mu.RUnlock()
+	sort.Strings(res)
+	return
+}
+
+// Get parses item key and calls registered handler.
+// Returns *zabbix.NotSupportedError for malformed or unknown keys and handler errors.
+func (r *Registry) Get(key string) (value string, err error) {
+	name, params, err := ParseKey(key)
+	if err != nil {
+		err = &zabbix.NotSupportedError{Key: key, Reason: err.Error()}
+		return
+	}
+
+	r.mu.RLock()
+	handler := r.handlers[name]
+	r.mu.RUnlock()
+	if handler == nil {
+		err = &zabbix.NotSupportedError{Key: key, Reason: "Unsupported item key."}
+		return
+	}
+
+	value, err = handler(params)
+	if err != nil {
+		if _, ok := err.(*zabbix.NotSupportedError); !ok {
+			err = &zabbix.NotSupportedError{Key: key, Reason: err.Error()}
+		}
+	}
+	return
+}
+
+// notSupportedReason extracts reason from error returned by Get.
+func notSupportedReason(err error) string {
+	if e, ok := err.(*zabbix.NotSupportedError); ok && e.Reason != "" {
+		return e.Reason
+	}
+	return fmt.Sprint(err)
+}
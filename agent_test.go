@@ -0,0 +1,87 @@
+package zabbix_test
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	. "."
+)
+
+// Starts fake agent which knows agent.ping and never answers agent.hang.
+func startAgent(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		defer l.Close()
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				b, err := ReadPacket(conn)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				switch strings.TrimSpace(string(b)) {
+				case "agent.ping":
+					WritePacket(conn, []byte("1"), false)
+				case "agent.hang":
+					time.Sleep(time.Second)
+				default:
+					WritePacket(conn, []byte("ZBX_NOTSUPPORTED\x00Unsupported item key."), false)
+				}
+			}()
+		}
+	}()
+	return l.Addr().String()
+}
+
+func TestAgentClient(t *testing.T) {
+	addr := startAgent(t)
+	host, port, _ := net.SplitHostPort(addr)
+
+	client, err := NewAgentClientForInterface(HostInterface{IP: host, Port: port, Type: Agent, UseIP: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.Timeout = 100 * time.Millisecond
+
+	value, err := client.Get("agent.ping")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value != "1" {
+		t.Errorf("Bad value: %q", value)
+	}
+
+	_, err = client.Get("no.such.key")
+	if e, ok := err.(*NotSupportedError); !ok || e.Key != "no.such.key" || e.Reason != "Unsupported item key." {
+		t.Errorf("Expected NotSupportedError, got %#v", err)
+	}
+
+	_, err = client.Get("agent.hang")
+	if e, ok := err.(net.Error); !ok || !e.Timeout() {
+		t.Errorf("Expected timeout, got %#v", err)
+	}
+
+	_, err = NewAgentClientForInterface(HostInterface{IP: host, Port: port, Type: SNMP, UseIP: 1})
+	if err == nil {
+		t.Error("Expected error for SNMP interface")
+	}
+
+	client, err = NewAgentClientForInterface(HostInterface{DNS: "agent.example.com", Type: Agent})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client.Addr != "agent.example.com:10050" {
+		t.Errorf("Bad address: %s", client.Addr)
+	}
+}
@@ -0,0 +1,54 @@
+package zabbix
+
+type (
+	AlertType   int
+	AlertStatus int
+)
+
+const (
+	AlertMessage AlertType = 0
+	AlertCommand AlertType = 1
+)
+
+const (
+	AlertNotSent AlertStatus = 0 // command is not executed
+	AlertSent    AlertStatus = 1 // command is executed
+	AlertFailed  AlertStatus = 2
+	AlertNew     AlertStatus = 3 // message is not processed by alert manager yet, Zabbix 3.4+
+)
+
+// Alert struct from https://www.zabbix.com/documentation/4.0/manual/api/reference/alert/object
+type Alert struct {
+	AlertId       string      `json:"alertid"`
+	ActionId      string      `json:"actionid"`
+	EventId       string      `json:"eventid"`
+	PEventId      string      `json:"p_eventid,omitempty"` // problem event for recovery alerts
+	AcknowledgeId string      `json:"acknowledgeid,omitempty"`
+	UserId        string      `json:"userid"`
+	Clock         int64       `json:"clock"`
+	MediaTypeId   string      `json:"mediatypeid"`
+	SendTo        string      `json:"sendto"`
+	Subject       string      `json:"subject"`
+	Message       string      `json:"message"`
+	Status        AlertStatus `json:"status"`
+	Retries       int         `json:"retries"`
+	Error         string      `json:"error"`
+	EscStep       int         `json:"esc_step"`
+	AlertType     AlertType   `json:"alerttype"`
+}
+
+type Alerts []Alert
+
+// AlertsGet is a wrapper for alert.get: https://www.zabbix.com/documentation/4.0/manual/api/reference/alert/get
+func (api *API) AlertsGet(params Params) (res Alerts, err error) {
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	response, err := api.CallWithError("alert.get", params)
+	if err != nil {
+		return
+	}
+
+	err = decode(response.Result.([]interface{}), &res)
+	return
+}
@@ -7,7 +7,10 @@ import (
 	"io/ioutil"
 	"log"
 	"net/http"
+	"sync"
 	"sync/atomic"
+
+	"github.com/mitchellh/mapstructure"
 )
 
 type (
@@ -54,12 +57,29 @@ func (e *ExpectedMore) Error() string {
 	return fmt.Sprintf("Expected %d, got %d.", e.Expected, e.Got)
 }
 
+// Decodes API result into output using json tags.
+// Zabbix returns most numbers as strings, so weakly typed input is allowed.
+func decode(input interface{}, output interface{}) (err error) {
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		WeaklyTypedInput: true,
+		TagName:          "json",
+		Result:           output,
+	})
+	if err != nil {
+		return
+	}
+	return decoder.Decode(input)
+}
+
 type API struct {
 	Auth   string      // auth token, filled by Login()
 	Logger *log.Logger // request/response logger, nil by default
 	url    string
 	c      http.Client
 	id     int32
+
+	versionM sync.Mutex
+	version  string // cached by versionAtLeast
 }
 
 // Creates new API access object.
@@ -81,9 +101,9 @@ func (api *API) printf(format string, v ...interface{}) {
 	}
 }
 
-func (api *API) callBytes(method string, params interface{}) (b []byte, err error) {
+func (api *API) callBytes(method string, params interface{}, auth string) (b []byte, err error) {
 	id := atomic.AddInt32(&api.id, 1)
-	jsonobj := request{"2.0", method, params, api.Auth, id}
+	jsonobj := request{"2.0", method, params, auth, id}
 	b, err = json.Marshal(jsonobj)
 	if err != nil {
 		return
@@ -113,7 +133,11 @@ func (api *API) callBytes(method string, params interface{}) (b []byte, err erro
 // Calls specified API method. Uses api.Auth if not empty.
 // err is something network or marshaling related. Caller should inspect response.Error to get API error.
 func (api *API) Call(method string, params interface{}) (response Response, err error) {
-	b, err := api.callBytes(method, params)
+	return api.call(method, params, api.Auth)
+}
+
+func (api *API) call(method string, params interface{}, auth string) (response Response, err error) {
+	b, err := api.callBytes(method, params, auth)
 	if err == nil {
 		err = json.Unmarshal(b, &response)
 	}
@@ -122,7 +146,11 @@ func (api *API) Call(method string, params interface{}) (response Response, err
 
 // Uses Call() and then sets err to response.Error if former is nil and latter is not.
 func (api *API) CallWithError(method string, params interface{}) (response Response, err error) {
-	response, err = api.Call(method, params)
+	return api.callWithError(method, params, api.Auth)
+}
+
+func (api *API) callWithError(method string, params interface{}, auth string) (response Response, err error) {
+	response, err = api.call(method, params, auth)
 	if err == nil && response.Error != nil {
 		err = response.Error
 	}
@@ -143,15 +171,11 @@ func (api *API) Login(user, password string) (auth string, err error) {
 	return
 }
 
-// Calls "APIInfo.version" API method.
-// This method temporary modifies API structure and should not be called concurrently with other methods.
+// Calls "APIInfo.version" API method without auth.
 func (api *API) Version() (v string, err error) {
-	// temporary remove auth for this method to succeed
+	// auth is not allowed for this method
 	// https://www.zabbix.com/documentation/2.2/manual/appendix/api/apiinfo/version
-	auth := api.Auth
-	api.Auth = ""
-	response, err := api.CallWithError("APIInfo.version", Params{})
-	api.Auth = auth
+	response, err := api.callWithError("APIInfo.version", Params{}, "")
 
 	// despite what documentation says, Zabbix 2.2 requires auth, so we try again
 	if e, ok := err.(*Error); ok && e.Code == -32602 {
@@ -165,6 +189,26 @@ func (api *API) Version() (v string, err error) {
 	return
 }
 
+// Checks that Zabbix API version is at least major.minor. Version is requested once and cached.
+func (api *API) versionAtLeast(major, minor int) (ok bool, err error) {
+	api.versionM.Lock()
+	if api.version == "" {
+		api.version, err = api.Version()
+	}
+	version := api.version
+	api.versionM.Unlock()
+	if err != nil {
+		return
+	}
+
+	var ma, mi int
+	if _, err = fmt.Sscanf(version, "%d.%d", &ma, &mi); err != nil {
+		return
+	}
+	ok = ma > major || (ma == major && mi >= minor)
+	return
+}
+
 // Calls "user.logout" API method.
 // This method modifies API structure and should not be called concurrently with other methods.
 func (api *API) Logout() (err error) {
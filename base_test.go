@@ -1,11 +1,14 @@
 package zabbix_test
 
 import (
+	"encoding/json"
 	"log"
 	"math/rand"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"regexp"
+	"sync"
 	"testing"
 	"time"
 
@@ -62,6 +65,24 @@ func getAPI(t *testing.T) *API {
 	return _api
 }
 
+// Starts fake API server answering methods with given JSON results ("[]" for other methods).
+// Returns API connected to it and pointer to received requests.
+func fakeAPI(t *testing.T, results map[string]string) (*API, *[]map[string]interface{}) {
+	requests := new([]map[string]interface{})
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&req)
+		*requests = append(*requests, req)
+		result, ok := results[req["method"].(string)]
+		if !ok {
+			result = "[]"
+		}
+		w.Write([]byte(`{"jsonrpc": "2.0", "id": 1, "result": ` + result + `}`))
+	}))
+	t.Cleanup(s.Close)
+	return NewAPI(s.URL), requests
+}
+
 func TestBadCalls(t *testing.T) {
 	api := getAPI(t)
 	res, err := api.Call("", nil)
@@ -100,3 +121,48 @@ func TestLogout(t *testing.T) {
 	}
 	_api = nil
 }
+
+func TestVersionConcurrent(t *testing.T) {
+	var m sync.Mutex
+	auths := make(map[string][]interface{}) // by method
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&req)
+		method := req["method"].(string)
+		m.Lock()
+		auths[method] = append(auths[method], req["auth"])
+		m.Unlock()
+		if method == "APIInfo.version" {
+			w.Write([]byte(`{"jsonrpc": "2.0", "id": 1, "result": "5.0.0"}`))
+			return
+		}
+		w.Write([]byte(`{"jsonrpc": "2.0", "id": 1, "result": []}`))
+	}))
+	defer s.Close()
+
+	api := NewAPI(s.URL)
+	api.Auth = "token"
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := api.MaintenancesGet(Params{}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if v := auths["APIInfo.version"]; len(v) != 1 || v[0] != nil {
+		t.Errorf("Unexpected version requests: %v", v)
+	}
+	for _, a := range auths["maintenance.get"] {
+		if a != "token" {
+			t.Errorf("Unexpected auth %v", a)
+		}
+	}
+	if len(auths["maintenance.get"]) != 10 {
+		t.Errorf("Unexpected requests: %v", auths)
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/seuf/zabbix"
+	"gopkg.in/yaml.v3"
+)
+
+// Profile describes one Zabbix server.
+// Token (API token of Zabbix 5.4+, or session ID) is used instead of user and password if set.
+type Profile struct {
+	URL      string `yaml:"url"`
+	User     string `yaml:"user,omitempty"`
+	Password string `yaml:"password,omitempty"`
+	Token    string `yaml:"token,omitempty"`
+}
+
+// Config is a content of configuration file:
+//
+//	current: prod
+//	profiles:
+//	  prod:
+//	    url: https://zabbix.example.com/api_jsonrpc.php
+//	    token: 0424bd59b807674191e7d77572075f33
+//	  lab:
+//	    url: http://localhost:8080/api_jsonrpc.php
+//	    user: Admin
+//	    password: zabbix
+type Config struct {
+	Current  string             `yaml:"current,omitempty"`
+	Profiles map[string]Profile `yaml:"profiles"`
+}
+
+// Returns configuration file name: $ZBXCTL_CONFIG or zbxctl/config.yaml in user configuration directory.
+func configPath() (string, error) {
+	if p := os.Getenv("ZBXCTL_CONFIG"); p != "" {
+		return p, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "zbxctl", "config.yaml"), nil
+}
+
+// Reads configuration file. Missing file is not an error.
+func loadConfig(path string) (c *Config, err error) {
+	c = new(Config)
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = yaml.Unmarshal(b, c); err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err)
+	}
+	return c, nil
+}
+
+// Returns profile by name, current profile if name is empty.
+// Flags override profile values.
+func (c *Config) profile(name string, override Profile) (p Profile, err error) {
+	if name == "" {
+		name = c.Current
+	}
+	if name != "" {
+		var ok bool
+		if p, ok = c.Profiles[name]; !ok {
+			return p, fmt.Errorf("Profile %q not found.", name)
+		}
+	}
+
+	if override.URL != "" {
+		p.URL = override.URL
+	}
+	if override.User != "" {
+		p.User, p.Token = override.User, ""
+	}
+	if override.Password != "" {
+		p.Password = override.Password
+	}
+	if override.Token != "" {
+		p.Token = override.Token
+	}
+	if p.URL == "" {
+		return p, fmt.Errorf("Zabbix API URL is not set, use -url flag or profile.")
+	}
+	return p, nil
+}
+
+// Returns API client logged in with profile credentials, and function to log out.
+func (p Profile) connect() (api *zabbix.API, logout func(), err error) {
+	api = zabbix.NewAPI(p.URL)
+	logout = func() {}
+	switch {
+	case p.Token != "":
+		api.Auth = p.Token
+	case p.User != "":
+		if _, err = api.Login(p.User, p.Password); err != nil {
+			return
+		}
+		logout = func() { api.Logout() }
+	}
+	return
+}
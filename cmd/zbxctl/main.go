@@ -0,0 +1,275 @@
+// Command zbxctl is a command-line client for Zabbix API.
+//
+// Usage:
+//
+//	zbxctl [flags] <resource> get [-filter field=value]... [-search field=value]... [-param name=json]... [-limit n] [name...]
+//	zbxctl [flags] <resource> create [-f file]
+//	zbxctl [flags] <resource> delete id...
+//	zbxctl [flags] events ack [-m message] id...
+//	zbxctl [flags] call <method> [params]
+//
+// Resources are hosts, groups, items, triggers, events, problems, maintenances, templates and actions.
+// Objects for create are read from file or standard input as JSON or YAML (object or list of objects),
+// params for call are JSON. Connection settings are taken from profile in configuration file, see Config.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/seuf/zabbix"
+)
+
+// keyValues is a repeatable flag of field=value pairs.
+type keyValues map[string]string
+
+func (kv keyValues) String() string {
+	var res []string
+	for k, v := range kv {
+		res = append(res, k+"="+v)
+	}
+	sort.Strings(res)
+	return strings.Join(res, ",")
+}
+
+func (kv keyValues) Set(s string) error {
+	parts := strings.SplitN(s, "=", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return fmt.Errorf("expected field=value, got %q", s)
+	}
+	kv[parts[0]] = parts[1]
+	return nil
+}
+
+// Converts keyValues to API params with string values.
+func (kv keyValues) params() zabbix.Params {
+	res := make(zabbix.Params, len(kv))
+	for k, v := range kv {
+		res[k] = v
+	}
+	return res
+}
+
+// Converts keyValues to API params, decoding values as JSON if possible.
+func (kv keyValues) jsonParams() zabbix.Params {
+	res := make(zabbix.Params, len(kv))
+	for k, v := range kv {
+		var j interface{}
+		if json.Unmarshal([]byte(v), &j) == nil {
+			res[k] = j
+		} else {
+			res[k] = v
+		}
+	}
+	return res
+}
+
+func usage(w io.Writer, flags *flag.FlagSet) {
+	var names []string
+	for name := range resources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintf(w, "Usage:\n")
+	fmt.Fprintf(w, "  zbxctl [flags] <resource> get [-filter field=value]... [-search field=value]... [-param name=json]... [-limit n] [name...]\n")
+	fmt.Fprintf(w, "  zbxctl [flags] <resource> create [-f file]\n")
+	fmt.Fprintf(w, "  zbxctl [flags] <resource> delete id...\n")
+	fmt.Fprintf(w, "  zbxctl [flags] events ack [-m message] id...\n")
+	fmt.Fprintf(w, "  zbxctl [flags] call <method> [params]\n\n")
+	fmt.Fprintf(w, "Resources: %s.\n\nFlags:\n", strings.Join(names, ", "))
+	flags.SetOutput(w)
+	flags.PrintDefaults()
+}
+
+// Runs command with given arguments (without program name).
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) (err error) {
+	var override Profile
+	flags := flag.NewFlagSet("zbxctl", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	config := flags.String("config", "", "configuration file (default $ZBXCTL_CONFIG or zbxctl/config.yaml in user configuration directory)")
+	profileName := flags.String("profile", os.Getenv("ZBXCTL_PROFILE"), "profile name (default $ZBXCTL_PROFILE or current profile)")
+	flags.StringVar(&override.URL, "url", "", "Zabbix API URL, overrides profile")
+	flags.StringVar(&override.User, "user", "", "user name, overrides profile")
+	flags.StringVar(&override.Password, "password", "", "password, overrides profile")
+	flags.StringVar(&override.Token, "token", "", "API token, overrides profile")
+	output := flags.String("o", string(tableFormat), "output format: table, json or yaml")
+	verbose := flags.Bool("v", false, "log API requests and responses to stderr")
+	flags.Usage = func() { usage(stderr, flags) }
+	if err = flags.Parse(args); err != nil {
+		return
+	}
+	args = flags.Args()
+	if len(args) < 2 {
+		flags.Usage()
+		return fmt.Errorf("expected resource and command")
+	}
+	f := format(*output)
+	if f != tableFormat && f != jsonFormat && f != yamlFormat {
+		return fmt.Errorf("unexpected output format %q", f)
+	}
+
+	path := *config
+	if path == "" {
+		if path, err = configPath(); err != nil {
+			return
+		}
+	}
+	c, err := loadConfig(path)
+	if err != nil {
+		return
+	}
+	profile, err := c.profile(*profileName, override)
+	if err != nil {
+		return
+	}
+	api, logout, err := profile.connect()
+	if err != nil {
+		return
+	}
+	defer logout()
+	if *verbose {
+		api.Logger = log.New(stderr, "", log.LstdFlags)
+	}
+
+	if args[0] == "call" {
+		return call(api, args[1:], stdout, f)
+	}
+	name, cmd, args := args[0], args[1], args[2:]
+	r := resources[name]
+	if r == nil {
+		return fmt.Errorf("unknown resource %q", name)
+	}
+	switch {
+	case cmd == "get":
+		return get(api, r, args, stdout, stderr, f)
+	case cmd == "create" && r.create != nil:
+		return create(api, r, args, stdin, stdout, stderr, f)
+	case cmd == "delete" && r.delete != nil:
+		if len(args) == 0 {
+			return fmt.Errorf("expected IDs to delete")
+		}
+		return r.delete(api, args)
+	case cmd == "ack" && r.ack != nil:
+		return ack(api, r, args, stderr)
+	}
+	return fmt.Errorf("%s does not support %q", name, cmd)
+}
+
+func get(api *zabbix.API, r *resource, args []string, stdout, stderr io.Writer, f format) (err error) {
+	filter, search, params := make(keyValues), make(keyValues), make(keyValues)
+	flags := flag.NewFlagSet("get", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Var(filter, "filter", "exact match `field=value`, repeatable")
+	flags.Var(search, "search", "substring match `field=value`, repeatable")
+	flags.Var(params, "param", "additional `name=json` parameter, repeatable")
+	limit := flags.Int("limit", 0, "maximum number of objects")
+	columns := flags.String("columns", strings.Join(r.columns, ","), "table columns")
+	if err = flags.Parse(args); err != nil {
+		return
+	}
+
+	p := params.jsonParams()
+	fp := filter.params()
+	if names := flags.Args(); len(names) > 0 {
+		fp[r.nameField] = names
+	}
+	if len(fp) > 0 {
+		p["filter"] = fp
+	}
+	if len(search) > 0 {
+		p["search"] = search.params()
+	}
+	if *limit > 0 {
+		p["limit"] = *limit
+	}
+
+	res, err := r.get(api, p)
+	if err != nil {
+		return
+	}
+	return write(stdout, f, res, strings.Split(*columns, ","))
+}
+
+func create(api *zabbix.API, r *resource, args []string, stdin io.Reader, stdout, stderr io.Writer, f format) (err error) {
+	flags := flag.NewFlagSet("create", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	file := flags.String("f", "-", "JSON or YAML `file` with objects to create, - for standard input")
+	if err = flags.Parse(args); err != nil {
+		return
+	}
+
+	var data []byte
+	if *file == "-" {
+		data, err = ioutil.ReadAll(stdin)
+	} else {
+		data, err = ioutil.ReadFile(*file)
+	}
+	if err != nil {
+		return
+	}
+
+	res, err := r.create(api, data)
+	if err != nil {
+		return
+	}
+	return write(stdout, f, res, r.columns)
+}
+
+func ack(api *zabbix.API, r *resource, args []string, stderr io.Writer) (err error) {
+	flags := flag.NewFlagSet("ack", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	message := flags.String("m", "", "acknowledgement message")
+	if err = flags.Parse(args); err != nil {
+		return
+	}
+	if flags.NArg() == 0 {
+		return fmt.Errorf("expected IDs to acknowledge")
+	}
+	return r.ack(api, flags.Args(), *message)
+}
+
+// Calls arbitrary API method with JSON params and writes result.
+func call(api *zabbix.API, args []string, stdout io.Writer, f format) (err error) {
+	if len(args) == 0 || len(args) > 2 {
+		return fmt.Errorf("expected method and optional JSON params")
+	}
+	var params interface{} = zabbix.Params{}
+	if len(args) == 2 {
+		if err = json.Unmarshal([]byte(args[1]), &params); err != nil {
+			return fmt.Errorf("params: %s", err)
+		}
+	}
+
+	// apiinfo.version is rejected by Zabbix if called with auth, Version calls it without
+	if strings.EqualFold(args[0], "apiinfo.version") {
+		var v string
+		if v, err = api.Version(); err != nil {
+			return
+		}
+		return write(stdout, f, v, nil)
+	}
+	response, err := api.CallWithError(args[0], params)
+	if err != nil {
+		return
+	}
+	return write(stdout, f, response.Result, nil)
+}
+
+func main() {
+	err := run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr)
+	if err == flag.ErrHelp {
+		return
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zbxctl: %s\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Starts fake API server returning given results by method, and records requests.
+// Like Zabbix, it rejects apiinfo methods called with auth.
+func fakeServer(t *testing.T, results map[string]string) (url string, requests *[]map[string]interface{}) {
+	requests = new([]map[string]interface{})
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&req)
+		*requests = append(*requests, req)
+		method := req["method"].(string)
+		if _, auth := req["auth"]; auth && strings.HasPrefix(strings.ToLower(method), "apiinfo.") {
+			w.Write([]byte(`{"jsonrpc": "2.0", "id": 1, "error": {"code": -32602, "message": "Invalid params.", ` +
+				`"data": "The \"` + method + `\" method must be called without the \"auth\" parameter."}}`))
+			return
+		}
+		result, ok := results[method]
+		if !ok {
+			result = "[]"
+		}
+		w.Write([]byte(`{"jsonrpc": "2.0", "id": 1, "result": ` + result + `}`))
+	}))
+	t.Cleanup(s.Close)
+	return s.URL, requests
+}
+
+// Runs zbxctl with configuration file pointing to url.
+func runWithConfig(t *testing.T, url string, stdin string, args ...string) (string, error) {
+	config := filepath.Join(t.TempDir(), "config.yaml")
+	data := "current: test\nprofiles:\n  test:\n    url: " + url + "\n    token: secret\n"
+	if err := ioutil.WriteFile(config, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	err := run(append([]string{"-config", config}, args...), strings.NewReader(stdin), &stdout, &stderr)
+	return stdout.String(), err
+}
+
+func TestHostsGet(t *testing.T) {
+	url, requests := fakeServer(t, map[string]string{
+		"host.get": `[{"hostid": "10084", "host": "Zabbix server", "name": "Zabbix server", "status": "0", "available": "1"}]`,
+	})
+
+	out, err := runWithConfig(t, url, "", "hosts", "get", "-filter", "status=0", "-search", "name=123", "-param", "limit=5", "Zabbix server")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "HOSTID  HOST           NAME           STATUS  AVAILABLE  MAINTENANCE_STATUS\n" +
+		"10084   Zabbix server  Zabbix server  0       1          0\n"
+	if out != expected {
+		t.Errorf("Unexpected output:\n%s", out)
+	}
+
+	req := (*requests)[0]
+	if req["auth"] != "secret" {
+		t.Errorf("Token is not used: %v", req)
+	}
+	params := req["params"].(map[string]interface{})
+	filter := params["filter"].(map[string]interface{})
+	if filter["status"] != "0" || filter["host"].([]interface{})[0] != "Zabbix server" {
+		t.Errorf("Unexpected filter: %v", filter)
+	}
+	if search := params["search"].(map[string]interface{}); search["name"] != "123" || params["limit"] != 5.0 {
+		t.Errorf("Unexpected params: %v", params)
+	}
+}
+
+func TestGroupsCreate(t *testing.T) {
+	url, requests := fakeServer(t, map[string]string{
+		"hostgroup.create": `{"groupids": ["42"]}`,
+	})
+
+	out, err := runWithConfig(t, url, "name: Web servers\n", "-o", "json", "groups", "create")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var groups []map[string]interface{}
+	if err = json.Unmarshal([]byte(out), &groups); err != nil {
+		t.Fatal(err)
+	}
+	if len(groups) != 1 || groups[0]["groupid"] != "42" || groups[0]["name"] != "Web servers" {
+		t.Errorf("Unexpected output:\n%s", out)
+	}
+	if (*requests)[0]["method"] != "hostgroup.create" {
+		t.Errorf("Unexpected request: %v", (*requests)[0])
+	}
+}
+
+func TestCall(t *testing.T) {
+	url, requests := fakeServer(t, map[string]string{
+		"apiinfo.version": `"6.0.0"`,
+		"APIInfo.version": `"6.0.0"`,
+	})
+
+	out, err := runWithConfig(t, url, "", "-o", "yaml", "call", "apiinfo.version")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "6.0.0\n" {
+		t.Errorf("Unexpected output: %q", out)
+	}
+
+	if _, err = runWithConfig(t, url, "", "call", "host.get", `{"a": 1}`); err != nil {
+		t.Fatal(err)
+	}
+	req := (*requests)[len(*requests)-1]
+	if a := req["params"].(map[string]interface{})["a"]; a != 1.0 || req["auth"] != "secret" {
+		t.Errorf("Unexpected request: %v", req)
+	}
+
+	if _, err = runWithConfig(t, url, "", "call", "apiinfo.version", "{"); err == nil {
+		t.Error("Expected error for invalid JSON")
+	}
+}
+
+func TestProfile(t *testing.T) {
+	c := &Config{Current: "prod", Profiles: map[string]Profile{
+		"prod": {URL: "https://prod/api_jsonrpc.php", Token: "t"},
+		"lab":  {URL: "http://lab/api_jsonrpc.php", User: "Admin", Password: "zabbix"},
+	}}
+
+	p, err := c.profile("", Profile{})
+	if err != nil || p.URL != "https://prod/api_jsonrpc.php" || p.Token != "t" {
+		t.Errorf("Unexpected current profile %#v: %v", p, err)
+	}
+	p, err = c.profile("prod", Profile{User: "Admin", Password: "secret"})
+	if err != nil || p.Token != "" || p.User != "Admin" || p.Password != "secret" {
+		t.Errorf("User flag doesn't override token: %#v, %v", p, err)
+	}
+	if _, err = c.profile("missing", Profile{}); err == nil {
+		t.Error("Expected error for missing profile")
+	}
+	if _, err = new(Config).profile("", Profile{}); err == nil {
+		t.Error("Expected error without URL")
+	}
+
+	c, err = loadConfig(filepath.Join(t.TempDir(), "missing.yaml"))
+	if err != nil || len(c.Profiles) != 0 {
+		t.Errorf("Missing configuration file: %#v, %v", c, err)
+	}
+	os.Setenv("ZBXCTL_CONFIG", "/tmp/zbxctl.yaml")
+	defer os.Unsetenv("ZBXCTL_CONFIG")
+	if path, _ := configPath(); path != "/tmp/zbxctl.yaml" {
+		t.Errorf("Unexpected path %q", path)
+	}
+}
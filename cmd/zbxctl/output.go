@@ -0,0 +1,124 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+type format string
+
+const (
+	tableFormat format = "table"
+	jsonFormat  format = "json"
+	yamlFormat  format = "yaml"
+)
+
+// columns formatted as time in tables
+var timeColumns = map[string]bool{"clock": true, "lastclock": true, "active_since": true, "active_till": true}
+
+// Converts value to JSON representation, so output uses API field names.
+func normalize(v interface{}) (res interface{}, err error) {
+	b, err := json.Marshal(v)
+	if err == nil {
+		err = json.Unmarshal(b, &res)
+	}
+	return
+}
+
+// Writes value in given format. Columns are used by table format; if empty, all fields are printed.
+func write(w io.Writer, f format, v interface{}, columns []string) (err error) {
+	if v, err = normalize(v); err != nil {
+		return
+	}
+
+	switch f {
+	case jsonFormat:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case yamlFormat:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		return enc.Encode(v)
+	case tableFormat:
+		return writeTable(w, v, columns)
+	}
+	panic("unexpected format " + f)
+}
+
+func writeTable(w io.Writer, v interface{}, columns []string) (err error) {
+	var rows []map[string]interface{}
+	switch v := v.(type) {
+	case []interface{}:
+		for _, o := range v {
+			row, ok := o.(map[string]interface{})
+			if !ok {
+				// list of scalars, for example, IDs
+				_, err = fmt.Fprintln(w, cell("", o))
+				if err != nil {
+					return
+				}
+				continue
+			}
+			rows = append(rows, row)
+		}
+	case map[string]interface{}:
+		rows = append(rows, v)
+	default:
+		_, err = fmt.Fprintln(w, cell("", v))
+		return
+	}
+	if len(rows) == 0 {
+		return
+	}
+
+	if len(columns) == 0 {
+		for k := range rows[0] {
+			columns = append(columns, k)
+		}
+		sort.Strings(columns)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.ToUpper(strings.Join(columns, "\t")))
+	for _, row := range rows {
+		cells := make([]string, len(columns))
+		for i, c := range columns {
+			cells[i] = cell(c, row[c])
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
+
+// Formats table cell.
+func cell(column string, v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		if timeColumns[column] {
+			if t, err := strconv.ParseInt(v, 10, 64); err == nil && t > 0 {
+				return time.Unix(t, 0).Format("2006-01-02 15:04:05")
+			}
+		}
+		return strings.Replace(v, "\n", " ", -1)
+	case float64:
+		if timeColumns[column] && v > 0 {
+			return time.Unix(int64(v), 0).Format("2006-01-02 15:04:05")
+		}
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []interface{}, map[string]interface{}:
+		b, _ := json.Marshal(v)
+		return string(b)
+	}
+	return fmt.Sprint(v)
+}
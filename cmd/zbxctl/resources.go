@@ -0,0 +1,180 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/seuf/zabbix"
+	"gopkg.in/yaml.v3"
+)
+
+// resource describes API objects of one kind for get, create and delete subcommands.
+type resource struct {
+	nameField string   // get matches arguments against this field
+	columns   []string // table columns
+
+	get    func(api *zabbix.API, params zabbix.Params) (interface{}, error)
+	create func(api *zabbix.API, data []byte) (interface{}, error)   // nil if not supported
+	delete func(api *zabbix.API, ids []string) error                 // nil if not supported
+	ack    func(api *zabbix.API, ids []string, message string) error // nil if not supported
+}
+
+// Decodes JSON or YAML object or list of objects into v (pointer to slice of API objects).
+func decodeInput(data []byte, v interface{}) (err error) {
+	// YAML is a superset of JSON
+	var input interface{}
+	if err = yaml.Unmarshal(data, &input); err != nil {
+		return
+	}
+	switch input.(type) {
+	case map[string]interface{}:
+		input = []interface{}{input}
+	case []interface{}:
+	default:
+		return fmt.Errorf("Expected object or list of objects.")
+	}
+
+	b, err := json.Marshal(input)
+	if err == nil {
+		err = json.Unmarshal(b, v)
+	}
+	return
+}
+
+var resources = map[string]*resource{
+	"hosts": {
+		nameField: "host",
+		columns:   []string{"hostid", "host", "name", "status", "available", "maintenance_status"},
+		get: func(api *zabbix.API, params zabbix.Params) (interface{}, error) {
+			return api.HostsGet(params)
+		},
+		create: func(api *zabbix.API, data []byte) (interface{}, error) {
+			var hosts zabbix.Hosts
+			if err := decodeInput(data, &hosts); err != nil {
+				return nil, err
+			}
+			return hosts, api.HostsCreate(hosts)
+		},
+		delete: func(api *zabbix.API, ids []string) error { return api.HostsDeleteByIds(ids) },
+	},
+
+	"groups": {
+		nameField: "name",
+		columns:   []string{"groupid", "name", "internal"},
+		get: func(api *zabbix.API, params zabbix.Params) (interface{}, error) {
+			return api.HostGroupsGet(params)
+		},
+		create: func(api *zabbix.API, data []byte) (interface{}, error) {
+			var groups zabbix.HostGroups
+			if err := decodeInput(data, &groups); err != nil {
+				return nil, err
+			}
+			return groups, api.HostGroupsCreate(groups)
+		},
+		delete: func(api *zabbix.API, ids []string) error { return api.HostGroupsDeleteByIds(ids) },
+	},
+
+	"items": {
+		nameField: "key_",
+		columns:   []string{"itemid", "hostid", "key_", "name", "type", "value_type", "delay"},
+		get: func(api *zabbix.API, params zabbix.Params) (interface{}, error) {
+			return api.ItemsGet(params)
+		},
+		create: func(api *zabbix.API, data []byte) (interface{}, error) {
+			var items zabbix.Items
+			if err := decodeInput(data, &items); err != nil {
+				return nil, err
+			}
+			return items, api.ItemsCreate(items)
+		},
+		delete: func(api *zabbix.API, ids []string) error { return api.ItemsDeleteByIds(ids) },
+	},
+
+	"triggers": {
+		nameField: "description",
+		columns:   []string{"triggerid", "description", "priority", "value", "expression"},
+		get: func(api *zabbix.API, params zabbix.Params) (interface{}, error) {
+			return api.TriggersGet(params)
+		},
+		create: func(api *zabbix.API, data []byte) (interface{}, error) {
+			var triggers zabbix.Triggers
+			if err := decodeInput(data, &triggers); err != nil {
+				return nil, err
+			}
+			return triggers, api.TriggersCreate(triggers)
+		},
+		delete: func(api *zabbix.API, ids []string) error { return api.TriggersDeleteByIds(ids) },
+	},
+
+	"events": {
+		nameField: "eventid",
+		columns:   []string{"eventid", "clock", "source", "object", "objectid", "value", "acknowledged"},
+		get: func(api *zabbix.API, params zabbix.Params) (interface{}, error) {
+			return api.EventsGet(params)
+		},
+		ack: func(api *zabbix.API, ids []string, message string) error {
+			for _, id := range ids {
+				if err := api.EventsAckByID(id, message); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+
+	"problems": {
+		nameField: "name",
+		columns:   []string{"eventid", "clock", "severity", "name", "acknowledged", "suppressed", "r_eventid"},
+		get: func(api *zabbix.API, params zabbix.Params) (interface{}, error) {
+			return api.ProblemsGet(params)
+		},
+	},
+
+	"maintenances": {
+		nameField: "name",
+		columns:   []string{"maintenanceid", "name", "maintenance_type", "active_since", "active_till"},
+		get: func(api *zabbix.API, params zabbix.Params) (interface{}, error) {
+			return api.MaintenancesGet(params)
+		},
+		create: func(api *zabbix.API, data []byte) (interface{}, error) {
+			var maintenances zabbix.Maintenances
+			if err := decodeInput(data, &maintenances); err != nil {
+				return nil, err
+			}
+			return maintenances, api.MaintenancesCreate(maintenances)
+		},
+		delete: func(api *zabbix.API, ids []string) error { return api.MaintenancesDeleteByIDs(ids) },
+	},
+
+	"templates": {
+		nameField: "host",
+		columns:   []string{"templateid", "host", "name", "description"},
+		get: func(api *zabbix.API, params zabbix.Params) (interface{}, error) {
+			return api.TemplatesGet(params)
+		},
+		create: func(api *zabbix.API, data []byte) (interface{}, error) {
+			var templates zabbix.Templates
+			if err := decodeInput(data, &templates); err != nil {
+				return nil, err
+			}
+			return templates, api.TemplatesCreate(templates)
+		},
+		delete: func(api *zabbix.API, ids []string) error { return api.TemplatesDeleteByIds(ids) },
+	},
+
+	"actions": {
+		nameField: "name",
+		columns:   []string{"actionid", "name", "eventsource", "status", "esc_period"},
+		get: func(api *zabbix.API, params zabbix.Params) (interface{}, error) {
+			return api.ActionGet(params)
+		},
+		create: func(api *zabbix.API, data []byte) (interface{}, error) {
+			var actions zabbix.Actions
+			if err := decodeInput(data, &actions); err != nil {
+				return nil, err
+			}
+			return actions, api.ActionsCreate(actions)
+		},
+		delete: func(api *zabbix.API, ids []string) error { return api.ActionsDeleteByIds(ids) },
+	},
+}
@@ -0,0 +1,143 @@
+package zabbix
+
+import "fmt"
+
+type (
+	ConfigurationFormat string
+)
+
+const (
+	XMLFormat  ConfigurationFormat = "xml"
+	JSONFormat ConfigurationFormat = "json"
+	YAMLFormat ConfigurationFormat = "yaml" // Zabbix 5.2+
+)
+
+// Objects to export by IDs: https://www.zabbix.com/documentation/4.0/manual/api/reference/configuration/export
+type ExportOptions struct {
+	Groups         []string `json:"groups,omitempty"` // before Zabbix 6.2
+	HostGroups     []string `json:"host_groups,omitempty"`
+	TemplateGroups []string `json:"template_groups,omitempty"`
+	Hosts          []string `json:"hosts,omitempty"`
+	Images         []string `json:"images,omitempty"`
+	Maps           []string `json:"maps,omitempty"`
+	MediaTypes     []string `json:"mediaTypes,omitempty"`
+	Screens        []string `json:"screens,omitempty"` // before Zabbix 5.4
+	Templates      []string `json:"templates,omitempty"`
+	ValueMaps      []string `json:"valueMaps,omitempty"` // before Zabbix 5.4
+}
+
+// ImportRule is a rule for one object class. Not every class supports every flag.
+type ImportRule struct {
+	CreateMissing  bool `json:"createMissing,omitempty"`
+	UpdateExisting bool `json:"updateExisting,omitempty"`
+	DeleteMissing  bool `json:"deleteMissing,omitempty"`
+}
+
+// Import rules: https://www.zabbix.com/documentation/4.0/manual/api/reference/configuration/import
+// Nil rules are not sent, so rules for object classes unknown to server version can be left empty.
+type ImportRules struct {
+	Applications       *ImportRule `json:"applications,omitempty"` // before Zabbix 5.4
+	DiscoveryRules     *ImportRule `json:"discoveryRules,omitempty"`
+	Graphs             *ImportRule `json:"graphs,omitempty"`
+	Groups             *ImportRule `json:"groups,omitempty"` // before Zabbix 6.2
+	HostGroups         *ImportRule `json:"host_groups,omitempty"`
+	TemplateGroups     *ImportRule `json:"template_groups,omitempty"`
+	Hosts              *ImportRule `json:"hosts,omitempty"`
+	HttpTests          *ImportRule `json:"httptests,omitempty"`
+	Images             *ImportRule `json:"images,omitempty"`
+	Items              *ImportRule `json:"items,omitempty"`
+	Maps               *ImportRule `json:"maps,omitempty"`
+	MediaTypes         *ImportRule `json:"mediaTypes,omitempty"`
+	Screens            *ImportRule `json:"screens,omitempty"` // before Zabbix 5.4
+	TemplateDashboards *ImportRule `json:"templateDashboards,omitempty"`
+	TemplateLinkage    *ImportRule `json:"templateLinkage,omitempty"`
+	Templates          *ImportRule `json:"templates,omitempty"`
+	TemplateScreens    *ImportRule `json:"templateScreens,omitempty"` // before Zabbix 5.4
+	Triggers           *ImportRule `json:"triggers,omitempty"`
+	ValueMaps          *ImportRule `json:"valueMaps,omitempty"`
+}
+
+// ImportDiff lists changes of one object class made by import.
+type ImportDiff struct {
+	Added   []map[string]interface{}
+	Removed []map[string]interface{}
+	Updated []ImportDiffUpdate
+}
+
+// ImportDiffUpdate is an updated object with changes of its nested objects by class.
+type ImportDiffUpdate struct {
+	Before   map[string]interface{}
+	After    map[string]interface{}
+	Children map[string]ImportDiff
+}
+
+func objects(v interface{}) (res []map[string]interface{}) {
+	list, _ := v.([]interface{})
+	for _, o := range list {
+		if m, ok := o.(map[string]interface{}); ok {
+			res = append(res, m)
+		}
+	}
+	return
+}
+
+func importDiff(v interface{}) (res ImportDiff) {
+	m, _ := v.(map[string]interface{})
+	res.Added, res.Removed = objects(m["added"]), objects(m["removed"])
+	for _, u := range objects(m["updated"]) {
+		update := ImportDiffUpdate{Children: make(map[string]ImportDiff)}
+		update.Before, _ = u["before"].(map[string]interface{})
+		update.After, _ = u["after"].(map[string]interface{})
+		for class, c := range u {
+			if class != "before" && class != "after" {
+				update.Children[class] = importDiff(c)
+			}
+		}
+		res.Updated = append(res.Updated, update)
+	}
+	return
+}
+
+// Wrapper for configuration.export: https://www.zabbix.com/documentation/4.0/manual/api/reference/configuration/export
+func (api *API) ConfigurationExport(format ConfigurationFormat, options ExportOptions) (data string, err error) {
+	response, err := api.CallWithError("configuration.export", Params{"format": format, "options": options})
+	if err != nil {
+		return
+	}
+
+	data = response.Result.(string)
+	return
+}
+
+// Wrapper for configuration.import: https://www.zabbix.com/documentation/4.0/manual/api/reference/configuration/import
+func (api *API) ConfigurationImport(format ConfigurationFormat, source string, rules ImportRules) (err error) {
+	_, err = api.CallWithError("configuration.import", Params{"format": format, "source": source, "rules": rules})
+	return
+}
+
+// Wrapper for configuration.importcompare: https://www.zabbix.com/documentation/6.0/manual/api/reference/configuration/importcompare
+// Returns changes ConfigurationImport would make by object class, without making them.
+// Requires Zabbix 6.0+.
+func (api *API) ConfigurationImportCompare(format ConfigurationFormat, source string, rules ImportRules) (res map[string]ImportDiff, err error) {
+	ok, err := api.versionAtLeast(6, 0)
+	if err != nil {
+		return
+	}
+	if !ok {
+		err = fmt.Errorf("configuration.importcompare requires Zabbix 6.0, got %s.", api.version)
+		return
+	}
+
+	response, err := api.CallWithError("configuration.importcompare", Params{"format": format, "source": source, "rules": rules})
+	if err != nil {
+		return
+	}
+
+	// empty result is encoded as an empty array
+	m, _ := response.Result.(map[string]interface{})
+	res = make(map[string]ImportDiff, len(m))
+	for class, d := range m {
+		res[class] = importDiff(d)
+	}
+	return
+}
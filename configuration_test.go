@@ -0,0 +1,37 @@
+package zabbix_test
+
+import (
+	"strings"
+	"testing"
+
+	. "."
+)
+
+func TestConfiguration(t *testing.T) {
+	api := getAPI(t)
+
+	group := CreateHostGroup(t)
+	defer DeleteHostGroup(group, t)
+
+	template := CreateTemplate(group, t)
+	defer DeleteTemplate(template, t)
+
+	for _, format := range []ConfigurationFormat{XMLFormat, JSONFormat} {
+		data, err := api.ConfigurationExport(format, ExportOptions{Templates: []string{template.TemplateId}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(data, template.Host) {
+			t.Errorf("Template is not exported as %s: %s", format, data)
+		}
+
+		rules := ImportRules{
+			Templates: &ImportRule{CreateMissing: true, UpdateExisting: true},
+			Items:     &ImportRule{CreateMissing: true, UpdateExisting: true, DeleteMissing: true},
+		}
+		err = api.ConfigurationImport(format, data, rules)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -1,11 +1,12 @@
 package zabbix
 
-import "github.com/mitchellh/mapstructure"
+import "fmt"
 
 type (
-	ObjectType     int
-	SourceType     int
-	EventValueType int
+	ObjectType            int
+	SourceType            int
+	EventValueType        int
+	AcknowledgeActionType int
 )
 
 const (
@@ -35,6 +36,17 @@ const (
 	SourceInternalUnknown         EventValueType = 1
 )
 
+// Actions of event.acknowledge, may be combined.
+const (
+	AckClose          AcknowledgeActionType = 1
+	AckAcknowledge    AcknowledgeActionType = 2
+	AckMessage        AcknowledgeActionType = 4
+	AckChangeSeverity AcknowledgeActionType = 8
+	AckUnacknowledge  AcknowledgeActionType = 16 // Zabbix 5.0+
+	AckSuppress       AcknowledgeActionType = 32 // Zabbix 6.2+
+	AckUnsuppress     AcknowledgeActionType = 64 // Zabbix 6.2+
+)
+
 // Acknowledge struct from https://www.zabbix.com/documentation/2.4/manual/api/reference/event/get?s[]=acknowledgeid
 type Acknowledge struct {
 	AcknowledgeId string `json:"acknowledgeid"`
@@ -45,6 +57,11 @@ type Acknowledge struct {
 	Name          string `json:"name,omitempty"`
 	SurName       string `json:"surname,omitempty"`
 	UserId        string `json:"userid,omitempty"`
+
+	// Zabbix 4.0+
+	Action      AcknowledgeActionType `json:"action,omitempty"`
+	OldSeverity PriorityType          `json:"old_severity,omitempty"`
+	NewSeverity PriorityType          `json:"new_severity,omitempty"`
 }
 
 type Acknowledges []Acknowledge
@@ -61,6 +78,12 @@ type Event struct {
 	Source       SourceType   `json:"source"`
 	Value        ValueType    `json:"value"`
 	Triggers     Triggers     `json:"triggers,omitempty"`
+	Hosts        Hosts        `json:"hosts,omitempty"`
+
+	// Zabbix 4.0+
+	REventId string       `json:"r_eventid,omitempty"` // recovery event ID of problem event, "0" if not resolved
+	Name     string       `json:"name,omitempty"`
+	Severity PriorityType `json:"severity,omitempty"`
 }
 
 type Events []Event
@@ -74,20 +97,7 @@ func (api *API) EventsGet(params Params) (res Events, err error) {
 	if err != nil {
 		return
 	}
-	res = make(Events, len(response.Result.([]interface{})))
-	for i, h := range response.Result.([]interface{}) {
-		h2 := h.(map[string]interface{})
-		mapstructure.Decode(h2, &res[1])
-
-		if triggers, ok := h2["triggers"]; ok {
-			mapstructure.Decode(triggers.([]interface{}), &res[i].Triggers)
-
-		}
-		if acknowledges, ok := h2["acknowledges"]; ok {
-			mapstructure.Decode(acknowledges.([]interface{}), &res[i].Acknowledges)
-		}
-	}
-
+	err = decode(response.Result.([]interface{}), &res)
 	return
 }
 
@@ -101,11 +111,107 @@ func (api *API) EventsGetByTriggerID(id string) (res Events, err error) {
 	return api.EventsGet(Params{"objectids": id})
 }
 
-// EventsAckByID acknowledges event using id and text message - https://www.zabbix.com/documentation/2.4/manual/api/reference/event/acknowledge
-func (api *API) EventsAckByID(id string, message string) (err error) {
-	_, err = api.CallWithError("event.acknowledge", Params{"eventids": id, "message": message})
+// AcknowledgeRequest describes update of events (problems) by event.acknowledge.
+type AcknowledgeRequest struct {
+	EventIds      []string
+	Action        AcknowledgeActionType
+	Message       string       // required for AckMessage
+	Severity      PriorityType // used with AckChangeSeverity
+	SuppressUntil int64        // used with AckSuppress, 0 to suppress indefinitely
+}
+
+// Checks request and returns minimal Zabbix version (major, minor) supporting it.
+func (r *AcknowledgeRequest) check() (major, minor int, err error) {
+	if len(r.EventIds) == 0 {
+		return 0, 0, fmt.Errorf("No events to acknowledge.")
+	}
+	if r.Action == 0 || r.Action&^(AckClose|AckAcknowledge|AckMessage|AckChangeSeverity|AckUnacknowledge|AckSuppress|AckUnsuppress) != 0 {
+		return 0, 0, fmt.Errorf("Unexpected acknowledge action %d.", r.Action)
+	}
+	if r.Action&AckMessage != 0 && r.Message == "" {
+		return 0, 0, fmt.Errorf("Acknowledge message is empty.")
+	}
+	if r.Action&AckChangeSeverity != 0 && (r.Severity < NotClassified || r.Severity > Disaster) {
+		return 0, 0, fmt.Errorf("Unexpected severity %d.", r.Severity)
+	}
+	if r.Action&AckAcknowledge != 0 && r.Action&AckUnacknowledge != 0 || r.Action&AckSuppress != 0 && r.Action&AckUnsuppress != 0 {
+		return 0, 0, fmt.Errorf("Conflicting acknowledge actions %d.", r.Action)
+	}
+
+	switch {
+	case r.Action&(AckSuppress|AckUnsuppress) != 0:
+		return 6, 2, nil
+	case r.Action&AckUnacknowledge != 0:
+		return 5, 0, nil
+	case r.Action&^(AckAcknowledge|AckMessage) != 0:
+		return 4, 0, nil
+	}
+	return 0, 0, nil
+}
+
+// EventsAcknowledge is a wrapper for event.acknowledge: https://www.zabbix.com/documentation/4.0/manual/api/reference/event/acknowledge
+// It returns IDs of updated events.
+// Before Zabbix 4.0 events can only be acknowledged with a message, so only AckAcknowledge and AckMessage
+// actions are supported, and message is required.
+func (api *API) EventsAcknowledge(r AcknowledgeRequest) (eventIds []string, err error) {
+	major, minor, err := r.check()
 	if err != nil {
 		return
 	}
+	modern, err := api.versionAtLeast(4, 0)
+	if err != nil {
+		return
+	}
+
+	params := Params{"eventids": r.EventIds, "message": r.Message}
+	if modern {
+		ok, err := api.versionAtLeast(major, minor)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, fmt.Errorf("Acknowledge action %d requires Zabbix %d.%d, got %s.", r.Action, major, minor, api.version)
+		}
+
+		params["action"] = r.Action
+		if r.Action&AckMessage == 0 {
+			delete(params, "message")
+		}
+		if r.Action&AckChangeSeverity != 0 {
+			params["severity"] = r.Severity
+		}
+		if r.Action&AckSuppress != 0 {
+			params["suppress_until"] = r.SuppressUntil
+		}
+	} else {
+		if major != 0 {
+			return nil, fmt.Errorf("Acknowledge action %d requires Zabbix %d.%d, got %s.", r.Action, major, minor, api.version)
+		}
+		if r.Message == "" {
+			return nil, fmt.Errorf("Acknowledge message is required by Zabbix %s.", api.version)
+		}
+	}
+
+	response, err := api.CallWithError("event.acknowledge", params)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	for _, id := range result["eventids"].([]interface{}) {
+		// Zabbix 2.x returns numbers
+		eventIds = append(eventIds, fmt.Sprint(id))
+	}
+	return
+}
+
+// EventsAckByID acknowledges event using id and text message - https://www.zabbix.com/documentation/2.4/manual/api/reference/event/acknowledge
+// Message may be empty since Zabbix 4.0.
+func (api *API) EventsAckByID(id string, message string) (err error) {
+	action := AckAcknowledge
+	if message != "" {
+		action |= AckMessage
+	}
+	_, err = api.EventsAcknowledge(AcknowledgeRequest{EventIds: []string{id}, Action: action, Message: message})
 	return
 }
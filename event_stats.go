@@ -0,0 +1,223 @@
+package zabbix
+
+import (
+	"sort"
+	"time"
+)
+
+// ProblemPeriod is a problem event paired with its recovery event.
+type ProblemPeriod struct {
+	Problem  Event
+	Recovery *Event // nil if problem is not resolved
+}
+
+type ProblemPeriods []ProblemPeriod
+
+func eventTime(e *Event) time.Time {
+	return time.Unix(e.Clock, e.Ns)
+}
+
+// Checks if event a happened before b.
+func eventBefore(a, b *Event) bool {
+	if a.Clock != b.Clock {
+		return a.Clock < b.Clock
+	}
+	if a.Ns != b.Ns {
+		return a.Ns < b.Ns
+	}
+	return eventIdAfter(b.EventId, a.EventId)
+}
+
+// Start returns time of problem event.
+func (p *ProblemPeriod) Start() time.Time {
+	return eventTime(&p.Problem)
+}
+
+// End returns time of recovery event, or now if problem is not resolved.
+func (p *ProblemPeriod) End(now time.Time) time.Time {
+	if p.Recovery == nil {
+		return now
+	}
+	return eventTime(p.Recovery)
+}
+
+// PairEvents pairs trigger problem events with recovery events and returns periods ordered by problem start.
+// Recovery event is found by r_eventid (Zabbix 4.0+) if it is present in events; otherwise problem is resolved
+// by the next OK event of the same trigger. Problems with r_eventid "0" are not resolved.
+func PairEvents(events Events) (res ProblemPeriods) {
+	sorted := make([]*Event, len(events))
+	byId := make(map[string]*Event, len(events))
+	for i := range events {
+		sorted[i] = &events[i]
+		byId[events[i].EventId] = &events[i]
+	}
+	sort.SliceStable(sorted, func(i, j int) bool { return eventBefore(sorted[i], sorted[j]) })
+
+	waiting := make(map[string][]int) // indexes of problems waiting for next OK event by trigger ID
+	for _, e := range sorted {
+		switch e.Value {
+		case TriggerProblem:
+			res = append(res, ProblemPeriod{Problem: *e})
+			if r, found := byId[e.REventId]; found {
+				res[len(res)-1].Recovery = r
+			} else if e.REventId != "0" {
+				waiting[e.ObjectId] = append(waiting[e.ObjectId], len(res)-1)
+			}
+		case TriggerOk:
+			for _, i := range waiting[e.ObjectId] {
+				res[i].Recovery = e
+			}
+			delete(waiting, e.ObjectId)
+		}
+	}
+	return
+}
+
+// ProblemStatsOptions describe window of ProblemStats.
+type ProblemStatsOptions struct {
+	From, Till   time.Time
+	FlapInterval time.Duration // problem starting within this interval after recovery of the same trigger is a flap, 5 minutes by default
+}
+
+// ProblemStats summarizes problems within window.
+// Overlapping problems are merged into one outage, so downtime is not counted twice.
+type ProblemStats struct {
+	Problems     int           // problems active within window
+	Resolved     int           // problems resolved within window
+	Outages      int           // merged problems started within window
+	Flaps        int           // problems started within FlapInterval after recovery of the same trigger
+	Downtime     time.Duration // time with at least one problem
+	MTTR         time.Duration // mean duration of resolved problems, including time before window
+	MTBF         time.Duration // uptime divided by number of outages, 0 without outages
+	Availability float64       // percentage of window without problems
+}
+
+// Stats computes statistics for all periods as for a single service.
+func (ps ProblemPeriods) Stats(opts ProblemStatsOptions) (res ProblemStats) {
+	window := opts.Till.Sub(opts.From)
+	if window <= 0 {
+		return
+	}
+	if opts.FlapInterval <= 0 {
+		opts.FlapInterval = 5 * time.Minute
+	}
+
+	type interval struct{ start, end, clipped time.Time }
+	var intervals []interval
+	var repair time.Duration
+	byTrigger := make(map[string][]ProblemPeriod)
+	for _, p := range ps {
+		start, end := p.Start(), p.End(opts.Till)
+		if p.Recovery != nil && end.After(opts.Till) {
+			end = opts.Till
+		}
+		if !start.Before(opts.Till) || !end.After(opts.From) {
+			continue
+		}
+		res.Problems++
+		if p.Recovery != nil && !eventTime(p.Recovery).After(opts.Till) {
+			res.Resolved++
+			repair += end.Sub(start)
+		}
+		clipped := start
+		if clipped.Before(opts.From) {
+			clipped = opts.From
+		}
+		intervals = append(intervals, interval{start, end, clipped})
+		byTrigger[p.Problem.ObjectId] = append(byTrigger[p.Problem.ObjectId], p)
+	}
+	if res.Resolved > 0 {
+		res.MTTR = repair / time.Duration(res.Resolved)
+	}
+
+	sort.Slice(intervals, func(i, j int) bool { return intervals[i].start.Before(intervals[j].start) })
+	for i := 0; i < len(intervals); {
+		cur := intervals[i]
+		for i++; i < len(intervals) && !intervals[i].start.After(cur.end); i++ {
+			if intervals[i].end.After(cur.end) {
+				cur.end = intervals[i].end
+			}
+		}
+		res.Downtime += cur.end.Sub(cur.clipped)
+		if !cur.start.Before(opts.From) {
+			res.Outages++
+		}
+	}
+
+	for _, periods := range byTrigger {
+		sort.SliceStable(periods, func(i, j int) bool { return eventBefore(&periods[i].Problem, &periods[j].Problem) })
+		for i := 1; i < len(periods); i++ {
+			prev := &periods[i-1]
+			start := periods[i].Start()
+			if prev.Recovery != nil && !start.Before(opts.From) && start.Sub(eventTime(prev.Recovery)) < opts.FlapInterval {
+				res.Flaps++
+			}
+		}
+	}
+
+	uptime := window - res.Downtime
+	if res.Outages > 0 {
+		res.MTBF = uptime / time.Duration(res.Outages)
+	}
+	res.Availability = 100 * float64(uptime) / float64(window)
+	return
+}
+
+// StatsBy groups periods by keys and computes statistics for every key.
+// Period may belong to several keys, for example, to several hosts.
+func (ps ProblemPeriods) StatsBy(keys func(p *ProblemPeriod) []string, opts ProblemStatsOptions) map[string]ProblemStats {
+	groups := make(map[string]ProblemPeriods)
+	for i := range ps {
+		for _, k := range keys(&ps[i]) {
+			groups[k] = append(groups[k], ps[i])
+		}
+	}
+	res := make(map[string]ProblemStats, len(groups))
+	for k, g := range groups {
+		res[k] = g.Stats(opts)
+	}
+	return res
+}
+
+// ByTrigger is a StatsBy key returning trigger ID.
+func ByTrigger(p *ProblemPeriod) []string {
+	return []string{p.Problem.ObjectId}
+}
+
+// ByHost is a StatsBy key returning host IDs of problem event.
+// Events should be requested with "selectHosts", or with "selectTriggers" and trigger hosts.
+func ByHost(p *ProblemPeriod) (res []string) {
+	for _, h := range p.Problem.Hosts {
+		res = append(res, h.HostId)
+	}
+	if len(res) > 0 {
+		return
+	}
+	for _, t := range p.Problem.Triggers {
+		for _, h := range t.Hosts {
+			res = append(res, h.HostId)
+		}
+	}
+	return
+}
+
+// ByHostGroup returns StatsBy key returning host group IDs of problem hosts (see ByHost).
+// Hosts should be requested with "selectGroups".
+func ByHostGroup(hosts Hosts) func(p *ProblemPeriod) []string {
+	groups := make(map[string]HostGroupIds, len(hosts))
+	for _, h := range hosts {
+		groups[h.HostId] = h.GroupIds
+	}
+	return func(p *ProblemPeriod) (res []string) {
+		seen := make(map[string]bool)
+		for _, id := range ByHost(p) {
+			for _, g := range groups[id] {
+				if !seen[g.GroupId] {
+					seen[g.GroupId] = true
+					res = append(res, g.GroupId)
+				}
+			}
+		}
+		return
+	}
+}
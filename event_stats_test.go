@@ -0,0 +1,65 @@
+package zabbix_test
+
+import (
+	"testing"
+	"time"
+
+	. "."
+)
+
+func TestProblemStats(t *testing.T) {
+	h1, h2 := Hosts{{HostId: "h1"}}, Hosts{{HostId: "h2"}}
+	events := Events{
+		{EventId: "7", ObjectId: "B", Value: TriggerProblem, Clock: 1900, REventId: "0", Hosts: h2},
+		{EventId: "1", ObjectId: "A", Value: TriggerProblem, Clock: 900, Hosts: h1},
+		{EventId: "2", ObjectId: "A", Value: TriggerOk, Clock: 1100, Hosts: h1},
+		{EventId: "3", ObjectId: "A", Value: TriggerProblem, Clock: 1150, Hosts: h1},
+		{EventId: "4", ObjectId: "A", Value: TriggerOk, Clock: 1200, Hosts: h1},
+		{EventId: "5", ObjectId: "B", Value: TriggerProblem, Clock: 1180, REventId: "6", Hosts: h2},
+		{EventId: "6", ObjectId: "B", Value: TriggerOk, Clock: 1400, Hosts: h2},
+	}
+
+	periods := PairEvents(events)
+	if len(periods) != 4 {
+		t.Fatalf("Expected 4 periods, got %#v", periods)
+	}
+	for i, r := range []string{"2", "4", "6", ""} {
+		p := periods[i]
+		if p.Recovery == nil && r != "" || p.Recovery != nil && p.Recovery.EventId != r {
+			t.Errorf("Unexpected recovery of %s: %#v", p.Problem.EventId, p.Recovery)
+		}
+	}
+
+	opts := ProblemStatsOptions{From: time.Unix(1000, 0), Till: time.Unix(2000, 0)}
+	s := periods.Stats(opts)
+	expected := ProblemStats{
+		Problems:     4,
+		Resolved:     3,
+		Outages:      2,
+		Flaps:        1,
+		Downtime:     450 * time.Second,
+		MTTR:         470 * time.Second / 3,
+		MTBF:         275 * time.Second,
+		Availability: 55,
+	}
+	if s != expected {
+		t.Errorf("Expected %+v, got %+v", expected, s)
+	}
+
+	byTrigger := periods.StatsBy(ByTrigger, opts)
+	if a := byTrigger["A"]; a.Downtime != 150*time.Second || a.Outages != 1 || a.Flaps != 1 || a.Availability != 85 {
+		t.Errorf("Unexpected stats of trigger A %+v", a)
+	}
+	byHost := periods.StatsBy(ByHost, opts)
+	if b := byHost["h2"]; b.Downtime != 320*time.Second || b.Problems != 2 || b.Flaps != 0 {
+		t.Errorf("Unexpected stats of host h2 %+v", b)
+	}
+	hosts := Hosts{
+		{HostId: "h1", GroupIds: HostGroupIds{{GroupId: "g1"}}},
+		{HostId: "h2", GroupIds: HostGroupIds{{GroupId: "g1"}, {GroupId: "g2"}}},
+	}
+	byGroup := periods.StatsBy(ByHostGroup(hosts), opts)
+	if byGroup["g1"] != s || byGroup["g2"] != byHost["h2"] {
+		t.Errorf("Unexpected stats by group %+v", byGroup)
+	}
+}
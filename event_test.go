@@ -0,0 +1,68 @@
+package zabbix_test
+
+import (
+	"testing"
+
+	. "."
+)
+
+func TestEventsAcknowledge(t *testing.T) {
+	api, requests := fakeAPI(t, map[string]string{
+		"APIInfo.version":   `"6.2.0"`,
+		"event.acknowledge": `{"eventids": ["1", "2"]}`,
+	})
+
+	ids, err := api.EventsAcknowledge(AcknowledgeRequest{
+		EventIds: []string{"1", "2"},
+		Action:   AckAcknowledge | AckChangeSeverity | AckSuppress,
+		Severity: NotClassified,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 2 || ids[0] != "1" || ids[1] != "2" {
+		t.Errorf("Unexpected event IDs %v", ids)
+	}
+	params := (*requests)[1]["params"].(map[string]interface{})
+	if params["action"] != 42.0 || params["severity"] != 0.0 || params["suppress_until"] != 0.0 {
+		t.Errorf("Unexpected params %v", params)
+	}
+	if _, present := params["message"]; present {
+		t.Errorf("Unexpected message in %v", params)
+	}
+
+	for _, r := range []AcknowledgeRequest{
+		{Action: AckAcknowledge},
+		{EventIds: []string{"1"}},
+		{EventIds: []string{"1"}, Action: AckMessage},
+		{EventIds: []string{"1"}, Action: AckAcknowledge | AckUnacknowledge},
+		{EventIds: []string{"1"}, Action: 128},
+	} {
+		if _, err = api.EventsAcknowledge(r); err == nil {
+			t.Errorf("Expected error for %#v", r)
+		}
+	}
+}
+
+func TestEventsAcknowledgeLegacy(t *testing.T) {
+	api, requests := fakeAPI(t, map[string]string{
+		"APIInfo.version":   `"3.0.0"`,
+		"event.acknowledge": `{"eventids": [1]}`,
+	})
+
+	if err := api.EventsAckByID("1", "looking"); err != nil {
+		t.Fatal(err)
+	}
+	params := (*requests)[1]["params"].(map[string]interface{})
+	if _, present := params["action"]; present || params["message"] != "looking" {
+		t.Errorf("Unexpected params %v", params)
+	}
+
+	if err := api.EventsAckByID("1", ""); err == nil {
+		t.Error("Expected error for empty message")
+	}
+	_, err := api.EventsAcknowledge(AcknowledgeRequest{EventIds: []string{"1"}, Action: AckClose})
+	if err == nil {
+		t.Error("Expected error for close action")
+	}
+}
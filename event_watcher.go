@@ -0,0 +1,342 @@
+package zabbix
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+type EventNotificationType int
+
+const (
+	ProblemOpened          EventNotificationType = 0
+	ProblemResolved        EventNotificationType = 1
+	ProblemAcknowledged    EventNotificationType = 2
+	ProblemSeverityChanged EventNotificationType = 3
+)
+
+func (t EventNotificationType) String() string {
+	switch t {
+	case ProblemOpened:
+		return "opened"
+	case ProblemResolved:
+		return "resolved"
+	case ProblemAcknowledged:
+		return "acknowledged"
+	case ProblemSeverityChanged:
+		return "severity changed"
+	}
+	return "unknown"
+}
+
+// EventNotification describes change of trigger problem found by EventWatcher.
+type EventNotification struct {
+	Type        EventNotificationType
+	Event       Event        // problem event
+	Recovery    *Event       // recovery event for ProblemResolved, nil if it was not fetched by the same poll
+	Acknowledge *Acknowledge // update for ProblemAcknowledged and ProblemSeverityChanged
+}
+
+// WatchedProblem is a state of open problem tracked by EventWatcher.
+type WatchedProblem struct {
+	ObjectId      string       `json:"objectid"`
+	Severity      PriorityType `json:"severity"`
+	AcknowledgeId string       `json:"acknowledgeid,omitempty"` // last seen acknowledge
+}
+
+// EventCursor is a position of EventWatcher; it is committed after notifications of a poll are handled.
+type EventCursor struct {
+	EventId  string                    `json:"eventid,omitempty"` // last seen event
+	Clock    int64                     `json:"clock"`             // used when EventId is empty
+	Problems map[string]WatchedProblem `json:"problems"`          // open problems by event ID
+}
+
+// EventCursorStore persists EventCursor between restarts.
+type EventCursorStore interface {
+	// Load returns saved cursor, or zero cursor if there is none.
+	Load() (EventCursor, error)
+	Save(EventCursor) error
+}
+
+// FileEventCursorStore keeps EventCursor in JSON file with given path.
+type FileEventCursorStore string
+
+// Load implements EventCursorStore.
+func (s FileEventCursorStore) Load() (c EventCursor, err error) {
+	b, err := ioutil.ReadFile(string(s))
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal(b, &c)
+	return
+}
+
+// Save implements EventCursorStore. File is replaced atomically.
+func (s FileEventCursorStore) Save(c EventCursor) (err error) {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return
+	}
+	f, err := ioutil.TempFile(filepath.Dir(string(s)), filepath.Base(string(s))+".*")
+	if err != nil {
+		return
+	}
+	defer os.Remove(f.Name())
+	if _, err = f.Write(b); err != nil {
+		f.Close()
+		return
+	}
+	if err = f.Close(); err != nil {
+		return
+	}
+	return os.Rename(f.Name(), string(s))
+}
+
+// EventWatcherOptions configure EventWatcher.
+type EventWatcherOptions struct {
+	Interval time.Duration    // between polls, 30 seconds by default
+	Since    time.Time        // start of events when there is no saved cursor, now by default
+	Store    EventCursorStore // optional
+	Params   Params           // additional event.get filters, for example, "hostids"
+}
+
+// EventWatcher tails trigger events and reports changes of problems.
+// Open problems found by the watcher are tracked to report their acknowledgement, severity change and resolution.
+type EventWatcher struct {
+	api  *API
+	opts EventWatcherOptions
+
+	m      sync.Mutex
+	cursor EventCursor
+	err    error
+}
+
+// NewEventWatcher creates watcher, loading cursor from opts.Store if it is set.
+func (api *API) NewEventWatcher(opts EventWatcherOptions) (w *EventWatcher, err error) {
+	if opts.Interval <= 0 {
+		opts.Interval = 30 * time.Second
+	}
+	if opts.Since.IsZero() {
+		opts.Since = time.Now()
+	}
+
+	var c EventCursor
+	if opts.Store != nil {
+		if c, err = opts.Store.Load(); err != nil {
+			return
+		}
+	}
+	if c.EventId == "" && c.Clock == 0 {
+		c.Clock = opts.Since.Unix()
+	}
+	if c.Problems == nil {
+		c.Problems = make(map[string]WatchedProblem)
+	}
+	w = &EventWatcher{api: api, opts: opts, cursor: c}
+	return
+}
+
+// Cursor returns copy of current cursor.
+func (w *EventWatcher) Cursor() EventCursor {
+	w.m.Lock()
+	defer w.m.Unlock()
+	c := w.cursor
+	c.Problems = make(map[string]WatchedProblem, len(w.cursor.Problems))
+	for id, p := range w.cursor.Problems {
+		c.Problems[id] = p
+	}
+	return c
+}
+
+// Checks if event ID a is greater than b; IDs are decimal numbers without leading zeros.
+func eventIdAfter(a, b string) bool {
+	if len(a) != len(b) {
+		return len(a) > len(b)
+	}
+	return a > b
+}
+
+// Poll fetches events once and returns notifications in order: opened problems, acknowledgements and
+// severity changes, resolved problems, and cursor after them. Events seen by polls before the last
+// committed cursor are skipped, so cursor should be passed to Commit after notifications are handled.
+func (w *EventWatcher) Poll() (res []EventNotification, c EventCursor, err error) {
+	c = w.Cursor()
+
+	params := Params{
+		"source":              SourceTrigger,
+		"object":              ObjectTrigger,
+		"select_acknowledges": "extend",
+		"sortfield":           []string{"eventid"},
+		"sortorder":           "ASC",
+	}
+	for k, v := range w.opts.Params {
+		params[k] = v
+	}
+	if c.EventId != "" {
+		params["eventid_from"] = c.EventId
+	} else {
+		params["time_from"] = c.Clock
+	}
+	events, err := w.api.EventsGet(params)
+	if err != nil {
+		return nil, EventCursor{}, err
+	}
+
+	recoveries := make(map[string]*Event) // by event ID
+	latestOk := make(map[string]string)   // recovery event ID by object ID, for Zabbix without r_eventid
+	for i := range events {
+		e := &events[i]
+		if c.EventId != "" && !eventIdAfter(e.EventId, c.EventId) {
+			continue
+		}
+		c.EventId, c.Clock = e.EventId, e.Clock
+
+		switch e.Value {
+		case TriggerProblem:
+			p := WatchedProblem{ObjectId: e.ObjectId, Severity: e.Severity}
+			for _, a := range e.Acknowledges {
+				if eventIdAfter(a.AcknowledgeId, p.AcknowledgeId) {
+					p.AcknowledgeId = a.AcknowledgeId
+				}
+			}
+			c.Problems[e.EventId] = p
+			res = append(res, EventNotification{Type: ProblemOpened, Event: *e})
+		case TriggerOk:
+			recoveries[e.EventId] = e
+			latestOk[e.ObjectId] = e.EventId
+		}
+	}
+
+	if len(c.Problems) == 0 {
+		return
+	}
+	ids := make([]string, 0, len(c.Problems))
+	for id := range c.Problems {
+		ids = append(ids, id)
+	}
+	problems, err := w.api.EventsGet(Params{
+		"eventids":            ids,
+		"select_acknowledges": "extend",
+		"sortfield":           []string{"eventid"},
+		"sortorder":           "ASC",
+	})
+	if err != nil {
+		return nil, EventCursor{}, err
+	}
+
+	found := make(map[string]bool, len(problems))
+	var resolved []EventNotification
+	for _, e := range problems {
+		found[e.EventId] = true
+		p := c.Problems[e.EventId]
+
+		acks := e.Acknowledges
+		sort.Slice(acks, func(i, j int) bool { return eventIdAfter(acks[j].AcknowledgeId, acks[i].AcknowledgeId) })
+		for i := range acks {
+			a := &acks[i]
+			if !eventIdAfter(a.AcknowledgeId, p.AcknowledgeId) {
+				continue
+			}
+			p.AcknowledgeId = a.AcknowledgeId
+			if a.Action == 0 || a.Action&AckAcknowledge != 0 { // before Zabbix 4.0 action is not set
+				res = append(res, EventNotification{Type: ProblemAcknowledged, Event: e, Acknowledge: a})
+			}
+			if a.Action&AckChangeSeverity != 0 && a.NewSeverity != a.OldSeverity {
+				p.Severity = a.NewSeverity
+				res = append(res, EventNotification{Type: ProblemSeverityChanged, Event: e, Acknowledge: a})
+			}
+		}
+		if e.REventId != "" { // Zabbix 4.0+ returns current severity
+			p.Severity = e.Severity
+		}
+		c.Problems[e.EventId] = p
+
+		rId := e.REventId
+		if rId == "" && eventIdAfter(latestOk[e.ObjectId], e.EventId) {
+			rId = latestOk[e.ObjectId]
+		}
+		if rId != "" && rId != "0" {
+			delete(c.Problems, e.EventId)
+			resolved = append(resolved, EventNotification{Type: ProblemResolved, Event: e, Recovery: recoveries[rId]})
+		}
+	}
+	for id := range c.Problems {
+		if !found[id] { // deleted by housekeeper or not accessible anymore
+			delete(c.Problems, id)
+		}
+	}
+
+	return append(res, resolved...), c, nil
+}
+
+// Commit stores cursor returned by Poll in watcher and opts.Store.
+func (w *EventWatcher) Commit(c EventCursor) (err error) {
+	if w.opts.Store != nil {
+		if err = w.opts.Store.Save(c); err != nil {
+			return
+		}
+	}
+	w.m.Lock()
+	w.cursor = c
+	w.m.Unlock()
+	return
+}
+
+// Watch polls events every opts.Interval and sends notifications to returned channel.
+// Cursor is committed only after all notifications of a poll are sent, so notifications not sent
+// before ctx is done or a crash are sent again after restart.
+// Failed poll or commit is retried on the next tick; use Err to get the error.
+// Channel is closed when ctx is done.
+func (w *EventWatcher) Watch(ctx context.Context) <-chan EventNotification {
+	ch := make(chan EventNotification)
+	go func() {
+		defer close(ch)
+
+		t := time.NewTicker(w.opts.Interval)
+		defer t.Stop()
+		for {
+			res, c, err := w.Poll()
+			if err == nil {
+				for _, n := range res {
+					select {
+					case ch <- n:
+					case <-ctx.Done():
+						w.setErr(ctx.Err())
+						return
+					}
+				}
+				err = w.Commit(c)
+			}
+			w.setErr(err)
+
+			select {
+			case <-t.C:
+			case <-ctx.Done():
+				w.setErr(ctx.Err())
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+func (w *EventWatcher) setErr(err error) {
+	w.m.Lock()
+	w.err = err
+	w.m.Unlock()
+}
+
+// Err returns error of the last poll or commit by Watch, or ctx error after Watch is stopped.
+func (w *EventWatcher) Err() error {
+	w.m.Lock()
+	defer w.m.Unlock()
+	return w.err
+}
@@ -0,0 +1,204 @@
+package zabbix_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "."
+)
+
+func TestEventWatcher(t *testing.T) {
+	// results of event.get for new events and for tracked problems by poll
+	polls := [][2]string{
+		{
+			`[{"eventid": "10", "objectid": "100", "value": "1", "clock": "1000", "severity": "3", "r_eventid": "0"},
+			  {"eventid": "11", "objectid": "200", "value": "0", "clock": "1001", "r_eventid": "0"}]`,
+			`[{"eventid": "10", "objectid": "100", "value": "1", "clock": "1000", "severity": "3", "r_eventid": "0", "acknowledges": []}]`,
+		},
+		{
+			`[{"eventid": "11", "objectid": "200", "value": "0", "clock": "1001", "r_eventid": "0"},
+			  {"eventid": "12", "objectid": "101", "value": "1", "clock": "1002", "severity": "2", "r_eventid": "0"}]`,
+			`[{"eventid": "10", "objectid": "100", "value": "1", "clock": "1000", "severity": "4", "r_eventid": "0",
+			   "acknowledges": [{"acknowledgeid": "5", "eventid": "10", "action": "10", "old_severity": "3", "new_severity": "4"}]},
+			  {"eventid": "12", "objectid": "101", "value": "1", "clock": "1002", "severity": "2", "r_eventid": "0", "acknowledges": []}]`,
+		},
+		{
+			`[{"eventid": "12", "objectid": "101", "value": "1", "clock": "1002", "severity": "2", "r_eventid": "0"},
+			  {"eventid": "13", "objectid": "100", "value": "0", "clock": "1003", "r_eventid": "0"}]`,
+			`[{"eventid": "10", "objectid": "100", "value": "1", "clock": "1000", "severity": "4", "r_eventid": "13",
+			   "acknowledges": [{"acknowledgeid": "5", "eventid": "10", "action": "10", "old_severity": "3", "new_severity": "4"}]},
+			  {"eventid": "12", "objectid": "101", "value": "1", "clock": "1002", "severity": "2", "r_eventid": "0", "acknowledges": []}]`,
+		},
+	}
+	poll := 0
+	var cursors []interface{}
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Params map[string]interface{} `json:"params"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		result := polls[poll][1]
+		if ids, present := req.Params["eventids"]; present {
+			// return only requested problems
+			var events []map[string]interface{}
+			json.Unmarshal([]byte(result), &events)
+			requested := make(map[interface{}]bool)
+			for _, id := range ids.([]interface{}) {
+				requested[id] = true
+			}
+			filtered := []map[string]interface{}{}
+			for _, e := range events {
+				if requested[e["eventid"]] {
+					filtered = append(filtered, e)
+				}
+			}
+			b, _ := json.Marshal(filtered)
+			result = string(b)
+		} else {
+			result = polls[poll][0]
+			if c, present := req.Params["eventid_from"]; present {
+				cursors = append(cursors, c)
+			} else {
+				cursors = append(cursors, req.Params["time_from"])
+			}
+		}
+		w.Write([]byte(`{"jsonrpc": "2.0", "id": 1, "result": ` + result + `}`))
+	}))
+	defer s.Close()
+	api := NewAPI(s.URL)
+
+	store := FileEventCursorStore(filepath.Join(t.TempDir(), "cursor.json"))
+	w, err := api.NewEventWatcher(EventWatcherOptions{Since: time.Unix(900, 0), Store: store})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for poll = range polls {
+		res, c, err := w.Poll()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if poll == 0 {
+			// not committed, so the next poll returns the same notifications
+			if _, _, err = w.Poll(); err != nil || w.Cursor().EventId != "" {
+				t.Fatalf("Cursor is advanced without commit: %#v, %v", w.Cursor(), err)
+			}
+		}
+		if err = w.Commit(c); err != nil {
+			t.Fatal(err)
+		}
+		for _, n := range res {
+			got = append(got, n.Type.String()+" "+n.Event.EventId)
+			switch n.Type {
+			case ProblemSeverityChanged:
+				if n.Acknowledge.NewSeverity != High {
+					t.Errorf("Unexpected acknowledge %#v", n.Acknowledge)
+				}
+			case ProblemResolved:
+				if n.Recovery == nil || n.Recovery.EventId != "13" {
+					t.Errorf("Unexpected recovery %#v", n.Recovery)
+				}
+			}
+		}
+	}
+
+	expected := []string{"opened 10", "opened 12", "acknowledged 10", "severity changed 10", "resolved 10"}
+	if len(got) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, got)
+	}
+	for i := range got {
+		if got[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected, got)
+			break
+		}
+	}
+	if len(cursors) != 4 || cursors[0] != 900.0 || cursors[1] != 900.0 || cursors[2] != "11" || cursors[3] != "12" {
+		t.Errorf("Unexpected cursors %v", cursors)
+	}
+
+	// restart
+	w, err = api.NewEventWatcher(EventWatcherOptions{Store: store})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := w.Cursor()
+	if _, open := c.Problems["12"]; c.EventId != "13" || len(c.Problems) != 1 || !open {
+		t.Errorf("Unexpected cursor %#v", c)
+	}
+
+	// repeated poll returns nothing new, channel is closed on cancellation
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := w.Watch(ctx)
+	cancel()
+	for n := range ch {
+		t.Errorf("Unexpected notification %#v", n)
+	}
+	if w.Err() != context.Canceled {
+		t.Errorf("Unexpected error %v", w.Err())
+	}
+}
+
+func TestEventWatcherRetry(t *testing.T) {
+	failed := false
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !failed {
+			failed = true
+			w.Write([]byte(`{"jsonrpc": "2.0", "id": 1, "error": {"code": -32500, "message": "Application error.", "data": "Database is down."}}`))
+			return
+		}
+		w.Write([]byte(`{"jsonrpc": "2.0", "id": 1, "result": [{"eventid": "10", "objectid": "100", "value": "1", "clock": "1000", "r_eventid": "0"}]}`))
+	}))
+	defer s.Close()
+
+	w, err := NewAPI(s.URL).NewEventWatcher(EventWatcherOptions{Interval: 10 * time.Millisecond, Since: time.Unix(900, 0)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := w.Watch(ctx)
+	select {
+	case n := <-ch:
+		if n.Type != ProblemOpened || n.Event.EventId != "10" {
+			t.Errorf("Unexpected notification %#v", n)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Watch stopped after failed poll")
+	}
+}
+
+func TestEventWatcherUndelivered(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"jsonrpc": "2.0", "id": 1, "result": [{"eventid": "10", "objectid": "100", "value": "1", "clock": "1000", "r_eventid": "0"}]}`))
+	}))
+	defer s.Close()
+	api := NewAPI(s.URL)
+
+	store := FileEventCursorStore(filepath.Join(t.TempDir(), "cursor.json"))
+	w, err := api.NewEventWatcher(EventWatcherOptions{Since: time.Unix(900, 0), Store: store})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := w.Watch(ctx)
+	time.Sleep(50 * time.Millisecond) // notification is not received
+	cancel()
+	for range ch {
+	}
+
+	// restart
+	w, err = api.NewEventWatcher(EventWatcherOptions{Since: time.Unix(900, 0), Store: store})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, _, err := w.Poll()
+	if err != nil || len(res) != 1 || res[0].Event.EventId != "10" {
+		t.Errorf("Undelivered notification is lost: %v, %v", res, err)
+	}
+}
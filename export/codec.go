@@ -0,0 +1,287 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/seuf/zabbix"
+	"gopkg.in/yaml.v3"
+)
+
+type document struct {
+	ZabbixExport *Export `json:"zabbix_export" yaml:"zabbix_export"`
+}
+
+// Unmarshal parses export document in given format.
+func Unmarshal(data []byte, format zabbix.ConfigurationFormat) (res *Export, err error) {
+	var doc document
+	switch format {
+	case zabbix.XMLFormat:
+		res = new(Export)
+		err = xml.Unmarshal(data, res)
+		res.XMLName = xml.Name{} // root element name is defined by the tag
+		return
+	case zabbix.JSONFormat:
+		err = json.Unmarshal(data, &doc)
+	case zabbix.YAMLFormat:
+		err = yaml.Unmarshal(data, &doc)
+	default:
+		err = fmt.Errorf("Unexpected format %q.", format)
+	}
+	if err == nil && doc.ZabbixExport == nil {
+		err = fmt.Errorf("No zabbix_export in %s document.", format)
+	}
+	res = doc.ZabbixExport
+	return
+}
+
+// Marshal formats export document in given format, suitable for configuration.import.
+func Marshal(e *Export, format zabbix.ConfigurationFormat) (data []byte, err error) {
+	switch format {
+	case zabbix.XMLFormat:
+		data, err = xml.MarshalIndent(e, "", "    ")
+		if err == nil {
+			data = append([]byte(xml.Header), append(data, '\n')...)
+		}
+	case zabbix.JSONFormat:
+		data, err = json.MarshalIndent(document{e}, "", "    ")
+	case zabbix.YAMLFormat:
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		err = enc.Encode(document{e})
+		data = buf.Bytes()
+	default:
+		err = fmt.Errorf("Unexpected format %q.", format)
+	}
+	return
+}
+
+// Returns JSON names of struct fields.
+func jsonNames(t reflect.Type) map[string]bool {
+	res := make(map[string]bool, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" {
+			res[name] = true
+		}
+	}
+	return res
+}
+
+// Unmarshals JSON object into v (pointer to struct without UnmarshalJSON method),
+// keeping fields unknown to v in extra.
+func unmarshalJSON(data []byte, v interface{}, extra *map[string]interface{}) (err error) {
+	if err = json.Unmarshal(data, v); err != nil {
+		return
+	}
+
+	var fields map[string]interface{}
+	if err = json.Unmarshal(data, &fields); err != nil {
+		return
+	}
+	known := jsonNames(reflect.TypeOf(v).Elem())
+	for name := range fields {
+		if known[name] {
+			delete(fields, name)
+		}
+	}
+	*extra = nil
+	if len(fields) > 0 {
+		*extra = fields
+	}
+	return
+}
+
+// Marshals v (struct without MarshalJSON method) into JSON object with extra fields.
+func marshalJSON(v interface{}, extra map[string]interface{}) (data []byte, err error) {
+	data, err = json.Marshal(v)
+	if err != nil || len(extra) == 0 {
+		return
+	}
+
+	var fields map[string]json.RawMessage
+	if err = json.Unmarshal(data, &fields); err != nil {
+		return
+	}
+	for name, value := range extra {
+		if _, ok := fields[name]; ok {
+			continue
+		}
+		if fields[name], err = json.Marshal(value); err != nil {
+			return
+		}
+	}
+	return json.Marshal(fields)
+}
+
+// types without methods for use in unmarshalJSON and marshalJSON
+type (
+	exportNoMethods        Export
+	templateNoMethods      Template
+	itemNoMethods          Item
+	triggerNoMethods       Trigger
+	graphNoMethods         Graph
+	discoveryRuleNoMethods DiscoveryRule
+
+	groupNoMethods             Group
+	nameNoMethods              Name
+	macroNoMethods             Macro
+	tagNoMethods               Tag
+	preprocessingStepNoMethods PreprocessingStep
+	itemKeyNoMethods           ItemKey
+	triggerDependencyNoMethods TriggerDependency
+	graphItemNoMethods         GraphItem
+	filterConditionNoMethods   FilterCondition
+	filterNoMethods            Filter
+	valueMappingNoMethods      ValueMapping
+	valueMapNoMethods          ValueMap
+)
+
+func (e *Export) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(data, (*exportNoMethods)(e), &e.Extra)
+}
+
+func (e Export) MarshalJSON() ([]byte, error) {
+	return marshalJSON(exportNoMethods(e), e.Extra)
+}
+
+func (t *Template) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(data, (*templateNoMethods)(t), &t.Extra)
+}
+
+func (t Template) MarshalJSON() ([]byte, error) {
+	return marshalJSON(templateNoMethods(t), t.Extra)
+}
+
+func (i *Item) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(data, (*itemNoMethods)(i), &i.Extra)
+}
+
+func (i Item) MarshalJSON() ([]byte, error) {
+	return marshalJSON(itemNoMethods(i), i.Extra)
+}
+
+func (t *Trigger) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(data, (*triggerNoMethods)(t), &t.Extra)
+}
+
+func (t Trigger) MarshalJSON() ([]byte, error) {
+	return marshalJSON(triggerNoMethods(t), t.Extra)
+}
+
+func (g *Graph) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(data, (*graphNoMethods)(g), &g.Extra)
+}
+
+func (g Graph) MarshalJSON() ([]byte, error) {
+	return marshalJSON(graphNoMethods(g), g.Extra)
+}
+
+func (d *DiscoveryRule) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(data, (*discoveryRuleNoMethods)(d), &d.Extra)
+}
+
+func (d DiscoveryRule) MarshalJSON() ([]byte, error) {
+	return marshalJSON(discoveryRuleNoMethods(d), d.Extra)
+}
+
+func (g *Group) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(data, (*groupNoMethods)(g), &g.Extra)
+}
+
+func (g Group) MarshalJSON() ([]byte, error) {
+	return marshalJSON(groupNoMethods(g), g.Extra)
+}
+
+func (n *Name) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(data, (*nameNoMethods)(n), &n.Extra)
+}
+
+func (n Name) MarshalJSON() ([]byte, error) {
+	return marshalJSON(nameNoMethods(n), n.Extra)
+}
+
+func (m *Macro) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(data, (*macroNoMethods)(m), &m.Extra)
+}
+
+func (m Macro) MarshalJSON() ([]byte, error) {
+	return marshalJSON(macroNoMethods(m), m.Extra)
+}
+
+func (t *Tag) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(data, (*tagNoMethods)(t), &t.Extra)
+}
+
+func (t Tag) MarshalJSON() ([]byte, error) {
+	return marshalJSON(tagNoMethods(t), t.Extra)
+}
+
+func (p *PreprocessingStep) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(data, (*preprocessingStepNoMethods)(p), &p.Extra)
+}
+
+func (p PreprocessingStep) MarshalJSON() ([]byte, error) {
+	return marshalJSON(preprocessingStepNoMethods(p), p.Extra)
+}
+
+func (k *ItemKey) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(data, (*itemKeyNoMethods)(k), &k.Extra)
+}
+
+func (k ItemKey) MarshalJSON() ([]byte, error) {
+	return marshalJSON(itemKeyNoMethods(k), k.Extra)
+}
+
+func (d *TriggerDependency) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(data, (*triggerDependencyNoMethods)(d), &d.Extra)
+}
+
+func (d TriggerDependency) MarshalJSON() ([]byte, error) {
+	return marshalJSON(triggerDependencyNoMethods(d), d.Extra)
+}
+
+func (i *GraphItem) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(data, (*graphItemNoMethods)(i), &i.Extra)
+}
+
+func (i GraphItem) MarshalJSON() ([]byte, error) {
+	return marshalJSON(graphItemNoMethods(i), i.Extra)
+}
+
+func (c *FilterCondition) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(data, (*filterConditionNoMethods)(c), &c.Extra)
+}
+
+func (c FilterCondition) MarshalJSON() ([]byte, error) {
+	return marshalJSON(filterConditionNoMethods(c), c.Extra)
+}
+
+func (f *Filter) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(data, (*filterNoMethods)(f), &f.Extra)
+}
+
+func (f Filter) MarshalJSON() ([]byte, error) {
+	return marshalJSON(filterNoMethods(f), f.Extra)
+}
+
+func (m *ValueMapping) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(data, (*valueMappingNoMethods)(m), &m.Extra)
+}
+
+func (m ValueMapping) MarshalJSON() ([]byte, error) {
+	return marshalJSON(valueMappingNoMethods(m), m.Extra)
+}
+
+func (m *ValueMap) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(data, (*valueMapNoMethods)(m), &m.Extra)
+}
+
+func (m ValueMap) MarshalJSON() ([]byte, error) {
+	return marshalJSON(valueMapNoMethods(m), m.Extra)
+}
@@ -0,0 +1,246 @@
+// Package export models Zabbix configuration export format used by configuration.export and
+// configuration.import, so templates can be generated and edited in code.
+//
+// All values are kept as strings, as the format changes between Zabbix versions: for example,
+// item type is "0" in 4.0 exports and "ZABBIX_PASSIVE" since 5.0.
+// Fields unknown to this package are kept in Extra (JSON and YAML) and XMLExtra (XML) of every object,
+// so they survive round-trip in the same format.
+package export
+
+import "encoding/xml"
+
+// Export is a root of export document: https://www.zabbix.com/documentation/4.0/manual/xml_export_import
+type Export struct {
+	XMLName        xml.Name   `json:"-" yaml:"-" xml:"zabbix_export"`
+	Version        string     `json:"version" yaml:"version" xml:"version"`
+	Date           string     `json:"date,omitempty" yaml:"date,omitempty" xml:"date,omitempty"`
+	Groups         []Group    `json:"groups,omitempty" yaml:"groups,omitempty" xml:"groups>group,omitempty"` // before Zabbix 6.2
+	TemplateGroups []Group    `json:"template_groups,omitempty" yaml:"template_groups,omitempty" xml:"template_groups>template_group,omitempty"`
+	HostGroups     []Group    `json:"host_groups,omitempty" yaml:"host_groups,omitempty" xml:"host_groups>host_group,omitempty"`
+	Templates      []Template `json:"templates,omitempty" yaml:"templates,omitempty" xml:"templates>template,omitempty"`
+	Triggers       []Trigger  `json:"triggers,omitempty" yaml:"triggers,omitempty" xml:"triggers>trigger,omitempty"`
+	Graphs         []Graph    `json:"graphs,omitempty" yaml:"graphs,omitempty" xml:"graphs>graph,omitempty"`
+	ValueMaps      []ValueMap `json:"value_maps,omitempty" yaml:"value_maps,omitempty" xml:"value_maps>value_map,omitempty"` // before Zabbix 5.4
+
+	Extra    map[string]interface{} `json:"-" yaml:",inline" xml:"-"`
+	XMLExtra []XMLNode              `json:"-" yaml:"-" xml:",any"`
+}
+
+// Group is a host or template group.
+type Group struct {
+	UUID string `json:"uuid,omitempty" yaml:"uuid,omitempty" xml:"uuid,omitempty"`
+	Name string `json:"name" yaml:"name" xml:"name"`
+
+	Extra    map[string]interface{} `json:"-" yaml:",inline" xml:"-"`
+	XMLExtra []XMLNode              `json:"-" yaml:"-" xml:",any"`
+}
+
+// Name references object by name: linked template, application or value map.
+type Name struct {
+	Name string `json:"name" yaml:"name" xml:"name"`
+
+	Extra    map[string]interface{} `json:"-" yaml:",inline" xml:"-"`
+	XMLExtra []XMLNode              `json:"-" yaml:"-" xml:",any"`
+}
+
+type Macro struct {
+	Macro       string `json:"macro" yaml:"macro" xml:"macro"`
+	Value       string `json:"value,omitempty" yaml:"value,omitempty" xml:"value,omitempty"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty" xml:"description,omitempty"`
+	Type        string `json:"type,omitempty" yaml:"type,omitempty" xml:"type,omitempty"`
+
+	Extra    map[string]interface{} `json:"-" yaml:",inline" xml:"-"`
+	XMLExtra []XMLNode              `json:"-" yaml:"-" xml:",any"`
+}
+
+type Tag struct {
+	Tag   string `json:"tag" yaml:"tag" xml:"tag"`
+	Value string `json:"value,omitempty" yaml:"value,omitempty" xml:"value,omitempty"`
+
+	Extra    map[string]interface{} `json:"-" yaml:",inline" xml:"-"`
+	XMLExtra []XMLNode              `json:"-" yaml:"-" xml:",any"`
+}
+
+type Template struct {
+	UUID           string          `json:"uuid,omitempty" yaml:"uuid,omitempty" xml:"uuid,omitempty"`
+	Template       string          `json:"template" yaml:"template" xml:"template"`
+	Name           string          `json:"name,omitempty" yaml:"name,omitempty" xml:"name,omitempty"`
+	Description    string          `json:"description,omitempty" yaml:"description,omitempty" xml:"description,omitempty"`
+	Templates      []Name          `json:"templates,omitempty" yaml:"templates,omitempty" xml:"templates>template,omitempty"`
+	Groups         []Group         `json:"groups,omitempty" yaml:"groups,omitempty" xml:"groups>group,omitempty"`
+	Applications   []Name          `json:"applications,omitempty" yaml:"applications,omitempty" xml:"applications>application,omitempty"` // before Zabbix 5.4
+	Items          []Item          `json:"items,omitempty" yaml:"items,omitempty" xml:"items>item,omitempty"`
+	DiscoveryRules []DiscoveryRule `json:"discovery_rules,omitempty" yaml:"discovery_rules,omitempty" xml:"discovery_rules>discovery_rule,omitempty"`
+	Macros         []Macro         `json:"macros,omitempty" yaml:"macros,omitempty" xml:"macros>macro,omitempty"`
+	Tags           []Tag           `json:"tags,omitempty" yaml:"tags,omitempty" xml:"tags>tag,omitempty"`
+	ValueMaps      []ValueMap      `json:"valuemaps,omitempty" yaml:"valuemaps,omitempty" xml:"valuemaps>valuemap,omitempty"` // since Zabbix 5.4
+
+	Extra    map[string]interface{} `json:"-" yaml:",inline" xml:"-"`
+	XMLExtra []XMLNode              `json:"-" yaml:"-" xml:",any"`
+}
+
+// PreprocessingStep uses Params before Zabbix 5.4 and Parameters since.
+type PreprocessingStep struct {
+	Type               string   `json:"type" yaml:"type" xml:"type"`
+	Params             string   `json:"params,omitempty" yaml:"params,omitempty" xml:"params,omitempty"`
+	Parameters         []string `json:"parameters,omitempty" yaml:"parameters,omitempty" xml:"parameters>parameter,omitempty"`
+	ErrorHandler       string   `json:"error_handler,omitempty" yaml:"error_handler,omitempty" xml:"error_handler,omitempty"`
+	ErrorHandlerParams string   `json:"error_handler_params,omitempty" yaml:"error_handler_params,omitempty" xml:"error_handler_params,omitempty"`
+
+	Extra    map[string]interface{} `json:"-" yaml:",inline" xml:"-"`
+	XMLExtra []XMLNode              `json:"-" yaml:"-" xml:",any"`
+}
+
+// ItemKey references item by host and key, for example, in graphs.
+type ItemKey struct {
+	Host string `json:"host,omitempty" yaml:"host,omitempty" xml:"host,omitempty"`
+	Key  string `json:"key" yaml:"key" xml:"key"`
+
+	Extra    map[string]interface{} `json:"-" yaml:",inline" xml:"-"`
+	XMLExtra []XMLNode              `json:"-" yaml:"-" xml:",any"`
+}
+
+// Item is also used for item prototypes.
+type Item struct {
+	UUID          string              `json:"uuid,omitempty" yaml:"uuid,omitempty" xml:"uuid,omitempty"`
+	Name          string              `json:"name" yaml:"name" xml:"name"`
+	Type          string              `json:"type,omitempty" yaml:"type,omitempty" xml:"type,omitempty"`
+	Key           string              `json:"key" yaml:"key" xml:"key"`
+	Delay         string              `json:"delay,omitempty" yaml:"delay,omitempty" xml:"delay,omitempty"`
+	History       string              `json:"history,omitempty" yaml:"history,omitempty" xml:"history,omitempty"`
+	Trends        string              `json:"trends,omitempty" yaml:"trends,omitempty" xml:"trends,omitempty"`
+	Status        string              `json:"status,omitempty" yaml:"status,omitempty" xml:"status,omitempty"`
+	ValueType     string              `json:"value_type,omitempty" yaml:"value_type,omitempty" xml:"value_type,omitempty"`
+	Units         string              `json:"units,omitempty" yaml:"units,omitempty" xml:"units,omitempty"`
+	Description   string              `json:"description,omitempty" yaml:"description,omitempty" xml:"description,omitempty"`
+	MasterItem    *ItemKey            `json:"master_item,omitempty" yaml:"master_item,omitempty" xml:"master_item,omitempty"`
+	Applications  []Name              `json:"applications,omitempty" yaml:"applications,omitempty" xml:"applications>application,omitempty"` // before Zabbix 5.4
+	ValueMap      *Name               `json:"valuemap,omitempty" yaml:"valuemap,omitempty" xml:"valuemap,omitempty"`
+	Preprocessing []PreprocessingStep `json:"preprocessing,omitempty" yaml:"preprocessing,omitempty" xml:"preprocessing>step,omitempty"`
+	Triggers      []Trigger           `json:"triggers,omitempty" yaml:"triggers,omitempty" xml:"triggers>trigger,omitempty"` // since Zabbix 5.4
+	Tags          []Tag               `json:"tags,omitempty" yaml:"tags,omitempty" xml:"tags>tag,omitempty"`
+
+	Extra    map[string]interface{} `json:"-" yaml:",inline" xml:"-"`
+	XMLExtra []XMLNode              `json:"-" yaml:"-" xml:",any"`
+}
+
+// TriggerDependency references trigger by name and expressions.
+type TriggerDependency struct {
+	Name               string `json:"name" yaml:"name" xml:"name"`
+	Expression         string `json:"expression" yaml:"expression" xml:"expression"`
+	RecoveryExpression string `json:"recovery_expression,omitempty" yaml:"recovery_expression,omitempty" xml:"recovery_expression,omitempty"`
+
+	Extra    map[string]interface{} `json:"-" yaml:",inline" xml:"-"`
+	XMLExtra []XMLNode              `json:"-" yaml:"-" xml:",any"`
+}
+
+// Trigger is also used for trigger prototypes.
+type Trigger struct {
+	UUID               string              `json:"uuid,omitempty" yaml:"uuid,omitempty" xml:"uuid,omitempty"`
+	Expression         string              `json:"expression" yaml:"expression" xml:"expression"`
+	RecoveryMode       string              `json:"recovery_mode,omitempty" yaml:"recovery_mode,omitempty" xml:"recovery_mode,omitempty"`
+	RecoveryExpression string              `json:"recovery_expression,omitempty" yaml:"recovery_expression,omitempty" xml:"recovery_expression,omitempty"`
+	Name               string              `json:"name" yaml:"name" xml:"name"`
+	OpData             string              `json:"opdata,omitempty" yaml:"opdata,omitempty" xml:"opdata,omitempty"`
+	URL                string              `json:"url,omitempty" yaml:"url,omitempty" xml:"url,omitempty"`
+	Status             string              `json:"status,omitempty" yaml:"status,omitempty" xml:"status,omitempty"`
+	Priority           string              `json:"priority,omitempty" yaml:"priority,omitempty" xml:"priority,omitempty"`
+	Description        string              `json:"description,omitempty" yaml:"description,omitempty" xml:"description,omitempty"`
+	Type               string              `json:"type,omitempty" yaml:"type,omitempty" xml:"type,omitempty"`
+	ManualClose        string              `json:"manual_close,omitempty" yaml:"manual_close,omitempty" xml:"manual_close,omitempty"`
+	Dependencies       []TriggerDependency `json:"dependencies,omitempty" yaml:"dependencies,omitempty" xml:"dependencies>dependency,omitempty"`
+	Tags               []Tag               `json:"tags,omitempty" yaml:"tags,omitempty" xml:"tags>tag,omitempty"`
+
+	Extra    map[string]interface{} `json:"-" yaml:",inline" xml:"-"`
+	XMLExtra []XMLNode              `json:"-" yaml:"-" xml:",any"`
+}
+
+type GraphItem struct {
+	SortOrder string  `json:"sortorder,omitempty" yaml:"sortorder,omitempty" xml:"sortorder,omitempty"`
+	DrawType  string  `json:"drawtype,omitempty" yaml:"drawtype,omitempty" xml:"drawtype,omitempty"`
+	Color     string  `json:"color,omitempty" yaml:"color,omitempty" xml:"color,omitempty"`
+	YAxisSide string  `json:"yaxisside,omitempty" yaml:"yaxisside,omitempty" xml:"yaxisside,omitempty"`
+	CalcFnc   string  `json:"calc_fnc,omitempty" yaml:"calc_fnc,omitempty" xml:"calc_fnc,omitempty"`
+	Type      string  `json:"type,omitempty" yaml:"type,omitempty" xml:"type,omitempty"`
+	Item      ItemKey `json:"item" yaml:"item" xml:"item"`
+
+	Extra    map[string]interface{} `json:"-" yaml:",inline" xml:"-"`
+	XMLExtra []XMLNode              `json:"-" yaml:"-" xml:",any"`
+}
+
+// Graph is also used for graph prototypes.
+type Graph struct {
+	UUID       string      `json:"uuid,omitempty" yaml:"uuid,omitempty" xml:"uuid,omitempty"`
+	Name       string      `json:"name" yaml:"name" xml:"name"`
+	Width      string      `json:"width,omitempty" yaml:"width,omitempty" xml:"width,omitempty"`
+	Height     string      `json:"height,omitempty" yaml:"height,omitempty" xml:"height,omitempty"`
+	Type       string      `json:"type,omitempty" yaml:"type,omitempty" xml:"type,omitempty"`
+	GraphItems []GraphItem `json:"graph_items,omitempty" yaml:"graph_items,omitempty" xml:"graph_items>graph_item,omitempty"`
+
+	Extra    map[string]interface{} `json:"-" yaml:",inline" xml:"-"`
+	XMLExtra []XMLNode              `json:"-" yaml:"-" xml:",any"`
+}
+
+type FilterCondition struct {
+	Macro     string `json:"macro" yaml:"macro" xml:"macro"`
+	Value     string `json:"value,omitempty" yaml:"value,omitempty" xml:"value,omitempty"`
+	Operator  string `json:"operator,omitempty" yaml:"operator,omitempty" xml:"operator,omitempty"`
+	FormulaId string `json:"formulaid,omitempty" yaml:"formulaid,omitempty" xml:"formulaid,omitempty"`
+
+	Extra    map[string]interface{} `json:"-" yaml:",inline" xml:"-"`
+	XMLExtra []XMLNode              `json:"-" yaml:"-" xml:",any"`
+}
+
+type Filter struct {
+	EvalType   string            `json:"evaltype,omitempty" yaml:"evaltype,omitempty" xml:"evaltype,omitempty"`
+	Formula    string            `json:"formula,omitempty" yaml:"formula,omitempty" xml:"formula,omitempty"`
+	Conditions []FilterCondition `json:"conditions,omitempty" yaml:"conditions,omitempty" xml:"conditions>condition,omitempty"`
+
+	Extra    map[string]interface{} `json:"-" yaml:",inline" xml:"-"`
+	XMLExtra []XMLNode              `json:"-" yaml:"-" xml:",any"`
+}
+
+type DiscoveryRule struct {
+	UUID              string              `json:"uuid,omitempty" yaml:"uuid,omitempty" xml:"uuid,omitempty"`
+	Name              string              `json:"name" yaml:"name" xml:"name"`
+	Type              string              `json:"type,omitempty" yaml:"type,omitempty" xml:"type,omitempty"`
+	Key               string              `json:"key" yaml:"key" xml:"key"`
+	Delay             string              `json:"delay,omitempty" yaml:"delay,omitempty" xml:"delay,omitempty"`
+	Status            string              `json:"status,omitempty" yaml:"status,omitempty" xml:"status,omitempty"`
+	Lifetime          string              `json:"lifetime,omitempty" yaml:"lifetime,omitempty" xml:"lifetime,omitempty"`
+	Description       string              `json:"description,omitempty" yaml:"description,omitempty" xml:"description,omitempty"`
+	MasterItem        *ItemKey            `json:"master_item,omitempty" yaml:"master_item,omitempty" xml:"master_item,omitempty"`
+	Filter            *Filter             `json:"filter,omitempty" yaml:"filter,omitempty" xml:"filter,omitempty"`
+	ItemPrototypes    []Item              `json:"item_prototypes,omitempty" yaml:"item_prototypes,omitempty" xml:"item_prototypes>item_prototype,omitempty"`
+	TriggerPrototypes []Trigger           `json:"trigger_prototypes,omitempty" yaml:"trigger_prototypes,omitempty" xml:"trigger_prototypes>trigger_prototype,omitempty"`
+	GraphPrototypes   []Graph             `json:"graph_prototypes,omitempty" yaml:"graph_prototypes,omitempty" xml:"graph_prototypes>graph_prototype,omitempty"`
+	Preprocessing     []PreprocessingStep `json:"preprocessing,omitempty" yaml:"preprocessing,omitempty" xml:"preprocessing>step,omitempty"`
+
+	Extra    map[string]interface{} `json:"-" yaml:",inline" xml:"-"`
+	XMLExtra []XMLNode              `json:"-" yaml:"-" xml:",any"`
+}
+
+type ValueMapping struct {
+	Type     string `json:"type,omitempty" yaml:"type,omitempty" xml:"type,omitempty"` // since Zabbix 5.4
+	Value    string `json:"value,omitempty" yaml:"value,omitempty" xml:"value,omitempty"`
+	NewValue string `json:"newvalue" yaml:"newvalue" xml:"newvalue"`
+
+	Extra    map[string]interface{} `json:"-" yaml:",inline" xml:"-"`
+	XMLExtra []XMLNode              `json:"-" yaml:"-" xml:",any"`
+}
+
+type ValueMap struct {
+	UUID     string         `json:"uuid,omitempty" yaml:"uuid,omitempty" xml:"uuid,omitempty"`
+	Name     string         `json:"name" yaml:"name" xml:"name"`
+	Mappings []ValueMapping `json:"mappings,omitempty" yaml:"mappings,omitempty" xml:"mappings>mapping,omitempty"`
+
+	Extra    map[string]interface{} `json:"-" yaml:",inline" xml:"-"`
+	XMLExtra []XMLNode              `json:"-" yaml:"-" xml:",any"`
+}
+
+// XMLNode keeps XML element unknown to this package as is.
+type XMLNode struct {
+	XMLName xml.Name
+	Attrs   []xml.Attr `xml:",any,attr"`
+	Content string     `xml:",innerxml"`
+}
@@ -0,0 +1,384 @@
+package export_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+
+	. "."
+	"github.com/seuf/zabbix"
+	"gopkg.in/yaml.v3"
+)
+
+func read(t *testing.T, file string, format zabbix.ConfigurationFormat) ([]byte, *Export) {
+	data, err := ioutil.ReadFile("testdata/" + file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := Unmarshal(data, format)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data, e
+}
+
+// Checks that e survives round-trip in given format.
+func roundTrip(t *testing.T, e *Export, format zabbix.ConfigurationFormat) []byte {
+	data, err := Marshal(e, format)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e2, err := Unmarshal(data, format)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(e, e2) {
+		t.Errorf("Exports are not equal after %s round-trip:\n%#v\n%#v", format, e, e2)
+	}
+	return data
+}
+
+func TestXML40(t *testing.T) {
+	_, e := read(t, "template_4.0.xml", zabbix.XMLFormat)
+	if e.Version != "4.0" || len(e.Templates) != 1 || len(e.Triggers) != 1 || len(e.Graphs) != 1 || len(e.ValueMaps) != 1 {
+		t.Fatalf("Bad export: %#v", e)
+	}
+	template := e.Templates[0]
+	if template.Template != "Template App Nginx" || len(template.Items) != 1 || len(template.DiscoveryRules) != 1 {
+		t.Fatalf("Bad template: %#v", template)
+	}
+	if item := template.Items[0]; item.Key != "nginx.requests" || item.ValueMap.Name != "Service state" || item.Applications[0].Name != "Nginx" {
+		t.Errorf("Bad item: %#v", item)
+	}
+	rule := template.DiscoveryRules[0]
+	if rule.Filter.Conditions[0].Macro != "{#UPSTREAM}" || len(rule.ItemPrototypes) != 1 || len(rule.TriggerPrototypes) != 1 {
+		t.Errorf("Bad discovery rule: %#v", rule)
+	}
+	if e.Triggers[0].Expression != "{Template App Nginx:nginx.requests.avg(5m)}>1000" || e.Triggers[0].Tags[0].Value != "nginx" {
+		t.Errorf("Bad trigger: %#v", e.Triggers[0])
+	}
+
+	data := string(roundTrip(t, e, zabbix.XMLFormat))
+	for _, s := range []string{"<inventory_link>0</inventory_link>", "<screens></screens>", "&gt;1000"} {
+		if !strings.Contains(data, s) {
+			t.Errorf("%s is lost:\n%s", s, data)
+		}
+	}
+
+	// known fields survive conversion to other formats
+	e.XMLExtra = nil
+	for _, format := range []zabbix.ConfigurationFormat{zabbix.JSONFormat, zabbix.YAMLFormat} {
+		data, err := Marshal(e, format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		e2, err := Unmarshal(data, format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(e.Templates[0].Items[0].Preprocessing, e2.Templates[0].Items[0].Preprocessing) ||
+			!reflect.DeepEqual(e.Triggers, e2.Triggers) || !reflect.DeepEqual(e.ValueMaps, e2.ValueMaps) {
+			t.Errorf("Exports are not equal after conversion to %s:\n%#v\n%#v", format, e, e2)
+		}
+	}
+}
+
+func TestJSON50(t *testing.T) {
+	original, e := read(t, "template_5.0.json", zabbix.JSONFormat)
+	template := e.Templates[0]
+	if template.Items[0].Type != "SIMPLE" || template.Items[0].Preprocessing[0].Parameters[0] != "10m" || len(template.Items[0].Triggers) != 1 {
+		t.Errorf("Bad item: %#v", template.Items[0])
+	}
+	if template.Items[0].Extra["request_method"] != "POST" || template.Extra["dashboards"] == nil {
+		t.Errorf("Unknown fields are lost: %#v", template)
+	}
+
+	data := roundTrip(t, e, zabbix.JSONFormat)
+	var expected, actual interface{}
+	json.Unmarshal(original, &expected)
+	json.Unmarshal(data, &actual)
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Documents are not equal:\n%s\n%s", original, data)
+	}
+}
+
+func TestYAML60(t *testing.T) {
+	original, e := read(t, "template_6.0.yaml", zabbix.YAMLFormat)
+	if e.Groups[0].UUID != "57b7ae836ca64446ba2c296389c009b7" {
+		t.Errorf("Bad groups: %#v", e.Groups)
+	}
+	template := e.Templates[0]
+	if len(template.ValueMaps) != 1 || template.ValueMaps[0].Mappings[1].Type != "DEFAULT" {
+		t.Errorf("Bad value maps: %#v", template.ValueMaps)
+	}
+	rule := template.DiscoveryRules[0]
+	if rule.MasterItem.Key != `redis.info["{$REDIS.CONN.URI}"]` {
+		t.Errorf("Bad master item: %#v", rule.MasterItem)
+	}
+	if rule.GraphPrototypes[0].GraphItems[0].Item.Key != `redis.db.keys["{#DB}"]` || rule.Extra["lld_macro_paths"] == nil {
+		t.Errorf("Bad discovery rule: %#v", rule)
+	}
+
+	data := roundTrip(t, e, zabbix.YAMLFormat)
+	var expected, actual interface{}
+	yaml.Unmarshal(original, &expected)
+	yaml.Unmarshal(data, &actual)
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Documents are not equal:\n%s\n%s", original, data)
+	}
+
+	// generated document
+	e = &Export{Version: "6.0", Groups: []Group{{Name: "Templates"}}, Templates: []Template{{
+		Template: "Generated",
+		Groups:   []Group{{Name: "Templates"}},
+		Items:    []Item{{Name: "Ping", Type: "ZABBIX_ACTIVE", Key: "agent.ping"}},
+	}}}
+	roundTrip(t, e, zabbix.XMLFormat)
+	roundTrip(t, e, zabbix.JSONFormat)
+	roundTrip(t, e, zabbix.YAMLFormat)
+}
+
+// Parses XML document into tree of texts and children by element name, skipping empty elements
+// which are not exported in other formats and are omitted by Marshal. Order of elements with
+// different names is not kept, as unknown elements are marshalled after known ones.
+func xmlTree(t *testing.T, data []byte) interface{} {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	var parse func() interface{}
+	parse = func() interface{} {
+		children := make(map[string][]interface{})
+		text := ""
+		for {
+			tok, err := d.Token()
+			if err == io.EOF {
+				return children
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			switch tok := tok.(type) {
+			case xml.StartElement:
+				if child := parse(); child != nil {
+					children[tok.Name.Local] = append(children[tok.Name.Local], child)
+				}
+			case xml.CharData:
+				text += string(tok)
+			case xml.EndElement:
+				if len(children) > 0 {
+					return children
+				}
+				if strings.TrimSpace(text) != "" {
+					return text
+				}
+				return nil
+			}
+		}
+	}
+	return parse()
+}
+
+// Checks that documents in given format are equal, ignoring formatting.
+func equalDocuments(t *testing.T, a, b []byte, format zabbix.ConfigurationFormat) bool {
+	var x, y interface{}
+	switch format {
+	case zabbix.XMLFormat:
+		x, y = xmlTree(t, a), xmlTree(t, b)
+	case zabbix.JSONFormat:
+		json.Unmarshal(a, &x)
+		json.Unmarshal(b, &y)
+	case zabbix.YAMLFormat:
+		yaml.Unmarshal(a, &x)
+		yaml.Unmarshal(b, &y)
+	}
+	return reflect.DeepEqual(x, y)
+}
+
+// YAML samples of Zabbix 4.0 and 5.0 are converted from their XML and JSON exports, as YAML export
+// appeared in Zabbix 5.2, and XML sample of Zabbix 6.0 is converted from its YAML export.
+func TestSamples(t *testing.T) {
+	for _, c := range []struct {
+		file     string
+		format   zabbix.ConfigurationFormat
+		template string
+	}{
+		{"template_4.0.xml", zabbix.XMLFormat, "Template App Nginx"},
+		{"template_4.0.yaml", zabbix.YAMLFormat, "Template App Nginx"},
+		{"template_5.0.json", zabbix.JSONFormat, "Template Module ICMP Ping"},
+		{"template_5.0.xml", zabbix.XMLFormat, "Template Module ICMP Ping"},
+		{"template_5.0.yaml", zabbix.YAMLFormat, "Template Module ICMP Ping"},
+		{"template_6.0.xml", zabbix.XMLFormat, "Redis by Zabbix agent 2"},
+		{"template_6.0.yaml", zabbix.YAMLFormat, "Redis by Zabbix agent 2"},
+	} {
+		original, e := read(t, c.file, c.format)
+		version := strings.TrimSuffix(strings.TrimPrefix(c.file, "template_"), "."+string(c.format))
+		if e.Version != version || len(e.Templates) != 1 || e.Templates[0].Template != c.template || len(e.Templates[0].Items) == 0 {
+			t.Errorf("%s: bad export %#v", c.file, e)
+			continue
+		}
+		if data := roundTrip(t, e, c.format); !equalDocuments(t, original, data, c.format) {
+			t.Errorf("%s: documents are not equal:\n%s\n%s", c.file, original, data)
+		}
+	}
+}
+
+func TestUnknownFields(t *testing.T) {
+	documents := map[zabbix.ConfigurationFormat]string{
+		zabbix.YAMLFormat: `zabbix_export:
+  version: '9.0'
+  template_groups:
+    - name: Templates
+      color: red
+  templates:
+    - template: T
+      templates:
+        - name: Parent
+          linked_by: policy
+      macros:
+        - macro: '{$A}'
+          scope: global
+      tags:
+        - tag: a
+          weight: '1'
+      items:
+        - name: I
+          key: i
+          valuemap:
+            name: V
+            uuid: 1f
+          preprocessing:
+            - type: JAVASCRIPT
+              timeout: 5s
+      discovery_rules:
+        - name: D
+          key: d
+          filter:
+            evaltype: AND
+            mode: strict
+            conditions:
+              - macro: '{#A}'
+                case_sensitive: 'NO'
+          graph_prototypes:
+            - name: G
+              graph_items:
+                - color: 1A7C11
+                  opacity: '50'
+                  item:
+                    key: i
+                    host_uuid: 2f
+          trigger_prototypes:
+            - expression: 'last(/T/i)=0'
+              name: TP
+              dependencies:
+                - name: TD
+                  expression: 'last(/T/i)=1'
+                  weight: '2'
+      valuemaps:
+        - name: V
+          owner: admin
+          mappings:
+            - newvalue: Up
+              comment: ok
+`,
+		zabbix.XMLFormat: `<zabbix_export>
+    <version>9.0</version>
+    <template_groups>
+        <template_group><name>Templates</name><color>red</color></template_group>
+    </template_groups>
+    <templates>
+        <template>
+            <template>T</template>
+            <templates><template><name>Parent</name><linked_by>policy</linked_by></template></templates>
+            <macros><macro><macro>{$A}</macro><scope>global</scope></macro></macros>
+            <tags><tag><tag>a</tag><weight>1</weight></tag></tags>
+            <items>
+                <item>
+                    <name>I</name>
+                    <key>i</key>
+                    <valuemap><name>V</name><uuid>1f</uuid></valuemap>
+                    <preprocessing><step><type>JAVASCRIPT</type><timeout>5s</timeout></step></preprocessing>
+                </item>
+            </items>
+            <discovery_rules>
+                <discovery_rule>
+                    <name>D</name>
+                    <key>d</key>
+                    <filter>
+                        <evaltype>AND</evaltype>
+                        <mode>strict</mode>
+                        <conditions><condition><macro>{#A}</macro><case_sensitive>NO</case_sensitive></condition></conditions>
+                    </filter>
+                    <trigger_prototypes>
+                        <trigger_prototype>
+                            <expression>last(/T/i)=0</expression>
+                            <name>TP</name>
+                            <dependencies>
+                                <dependency><name>TD</name><expression>last(/T/i)=1</expression><weight>2</weight></dependency>
+                            </dependencies>
+                        </trigger_prototype>
+                    </trigger_prototypes>
+                    <graph_prototypes>
+                        <graph_prototype>
+                            <name>G</name>
+                            <graph_items>
+                                <graph_item><color>1A7C11</color><opacity>50</opacity><item><key>i</key><host_uuid>2f</host_uuid></item></graph_item>
+                            </graph_items>
+                        </graph_prototype>
+                    </graph_prototypes>
+                </discovery_rule>
+            </discovery_rules>
+            <valuemaps>
+                <valuemap><name>V</name><owner>admin</owner><mappings><mapping><newvalue>Up</newvalue><comment>ok</comment></mapping></mappings></valuemap>
+            </valuemaps>
+        </template>
+    </templates>
+</zabbix_export>
+`,
+	}
+	var b bytes.Buffer
+	json.NewEncoder(&b).Encode(map[string]interface{}{"zabbix_export": func() interface{} {
+		var doc map[string]interface{}
+		yaml.Unmarshal([]byte(documents[zabbix.YAMLFormat]), &doc)
+		return doc["zabbix_export"]
+	}()})
+	documents[zabbix.JSONFormat] = b.String()
+
+	for format, document := range documents {
+		e, err := Unmarshal([]byte(document), format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		template := e.Templates[0]
+		rule := template.DiscoveryRules[0]
+		graphItem := rule.GraphPrototypes[0].GraphItems[0]
+		for name, o := range map[string]struct {
+			extra    map[string]interface{}
+			xmlExtra []XMLNode
+		}{
+			"group":             {e.TemplateGroups[0].Extra, e.TemplateGroups[0].XMLExtra},
+			"linked template":   {template.Templates[0].Extra, template.Templates[0].XMLExtra},
+			"macro":             {template.Macros[0].Extra, template.Macros[0].XMLExtra},
+			"tag":               {template.Tags[0].Extra, template.Tags[0].XMLExtra},
+			"value map name":    {template.Items[0].ValueMap.Extra, template.Items[0].ValueMap.XMLExtra},
+			"preprocessing":     {template.Items[0].Preprocessing[0].Extra, template.Items[0].Preprocessing[0].XMLExtra},
+			"filter":            {rule.Filter.Extra, rule.Filter.XMLExtra},
+			"filter condition":  {rule.Filter.Conditions[0].Extra, rule.Filter.Conditions[0].XMLExtra},
+			"graph item":        {graphItem.Extra, graphItem.XMLExtra},
+			"item key":          {graphItem.Item.Extra, graphItem.Item.XMLExtra},
+			"dependency":        {rule.TriggerPrototypes[0].Dependencies[0].Extra, rule.TriggerPrototypes[0].Dependencies[0].XMLExtra},
+			"value map":         {template.ValueMaps[0].Extra, template.ValueMaps[0].XMLExtra},
+			"value map mapping": {template.ValueMaps[0].Mappings[0].Extra, template.ValueMaps[0].Mappings[0].XMLExtra},
+		} {
+			if (format == zabbix.XMLFormat && len(o.xmlExtra) != 1) || (format != zabbix.XMLFormat && len(o.extra) != 1) {
+				t.Errorf("%s: unknown field of %s is lost: %#v %#v", format, name, o.extra, o.xmlExtra)
+			}
+		}
+
+		if data := roundTrip(t, e, format); !equalDocuments(t, []byte(document), data, format) {
+			t.Errorf("%s: documents are not equal:\n%s\n%s", format, document, data)
+		}
+	}
+}
@@ -1,13 +1,16 @@
 package zabbix
 
-import "github.com/mitchellh/mapstructure"
+import (
+	"fmt"
+	"strconv"
+)
 
 // https://www.zabbix.com/documentation/2.4/manual/api/reference/history/object
 type History struct {
 	Clock  uint   `json:"clock"`
 	ItemId string `json:"itemid"`
 	Ns     int    `json:"ns"`
-	Value  string `json:"value"` // Currently always returns strings
+	Value  string `json:"value"` // Currently always returns strings, see Float() and Unsigned()
 
 	Id         string `json:"id,omitempty"`
 	LogEventId int    `json:"logeventid,omitempty"`
@@ -18,8 +21,78 @@ type History struct {
 
 type Histories []History
 
-// Wrapper for item.get https://www.zabbix.com/documentation/2.0/manual/appendix/api/item/get
-func (api *API) HistoriesGet(params Params) (res Histories, err error) {
+// Float parses value of history with Float value type.
+func (h History) Float() (float64, error) {
+	return strconv.ParseFloat(h.Value, 64)
+}
+
+// Unsigned parses value of history with Unsigned value type.
+func (h History) Unsigned() (uint64, error) {
+	return strconv.ParseUint(h.Value, 10, 64)
+}
+
+// History of items with Float value type.
+type FloatHistory struct {
+	Clock  uint    `json:"clock"`
+	ItemId string  `json:"itemid"`
+	Ns     int     `json:"ns"`
+	Value  float64 `json:"value"`
+}
+
+type FloatHistories []FloatHistory
+
+// History of items with Character value type.
+type CharacterHistory struct {
+	Clock  uint   `json:"clock"`
+	ItemId string `json:"itemid"`
+	Ns     int    `json:"ns"`
+	Value  string `json:"value"`
+}
+
+type CharacterHistories []CharacterHistory
+
+// History of items with Log value type.
+type LogHistory struct {
+	Id         string `json:"id"`
+	Clock      uint   `json:"clock"`
+	ItemId     string `json:"itemid"`
+	Ns         int    `json:"ns"`
+	Value      string `json:"value"`
+	LogEventId int    `json:"logeventid"`
+	Severity   int    `json:"severity"`
+	Source     string `json:"source"`
+	Timestamp  uint   `json:"timestamp"`
+}
+
+type LogHistories []LogHistory
+
+// History of items with Unsigned value type.
+type UnsignedHistory struct {
+	Clock  uint   `json:"clock"`
+	ItemId string `json:"itemid"`
+	Ns     int    `json:"ns"`
+	Value  uint64 `json:"value"`
+}
+
+type UnsignedHistories []UnsignedHistory
+
+// History of items with Text value type.
+type TextHistory struct {
+	Id     string `json:"id"`
+	Clock  uint   `json:"clock"`
+	ItemId string `json:"itemid"`
+	Ns     int    `json:"ns"`
+	Value  string `json:"value"`
+}
+
+type TextHistories []TextHistory
+
+// Calls history.get and decodes result into res.
+// "history" parameter selects value table, without it Zabbix silently returns Unsigned values.
+func (api *API) historyGet(params Params, res interface{}) (err error) {
+	if _, present := params["history"]; !present {
+		return fmt.Errorf("history.get requires \"history\" parameter (one of ValueType constants)")
+	}
 	if _, present := params["output"]; !present {
 		params["output"] = "extend"
 	}
@@ -28,7 +101,92 @@ func (api *API) HistoriesGet(params Params) (res Histories, err error) {
 		return
 	}
 
-	mapstructure.Decode(response.Result.([]interface{}), &res)
+	return decode(response.Result.([]interface{}), res)
+}
+
+// Wrapper for history.get: https://www.zabbix.com/documentation/2.4/manual/api/reference/history/get
+// params must contain "history" with one of ValueType constants.
+func (api *API) HistoriesGet(params Params) (res Histories, err error) {
+	err = api.historyGet(params, &res)
+	return
+}
+
+// Sets "history" parameter to t and calls historyGet, params may be nil.
+func (api *API) typedHistoryGet(t ValueType, params Params, res interface{}) error {
+	if params == nil {
+		params = Params{}
+	}
+	params["history"] = t
+	return api.historyGet(params, res)
+}
+
+// FloatHistoriesGet gets history of items with Float value type, params may be nil.
+func (api *API) FloatHistoriesGet(params Params) (res FloatHistories, err error) {
+	err = api.typedHistoryGet(Float, params, &res)
+	return
+}
+
+// CharacterHistoriesGet gets history of items with Character value type, params may be nil.
+func (api *API) CharacterHistoriesGet(params Params) (res CharacterHistories, err error) {
+	err = api.typedHistoryGet(Character, params, &res)
+	return
+}
 
+// LogHistoriesGet gets history of items with Log value type, params may be nil.
+func (api *API) LogHistoriesGet(params Params) (res LogHistories, err error) {
+	err = api.typedHistoryGet(Log, params, &res)
 	return
 }
+
+// UnsignedHistoriesGet gets history of items with Unsigned value type, params may be nil.
+func (api *API) UnsignedHistoriesGet(params Params) (res UnsignedHistories, err error) {
+	err = api.typedHistoryGet(Unsigned, params, &res)
+	return
+}
+
+// TextHistoriesGet gets history of items with Text value type, params may be nil.
+func (api *API) TextHistoriesGet(params Params) (res TextHistories, err error) {
+	err = api.typedHistoryGet(Text, params, &res)
+	return
+}
+
+// TypedHistories is one of FloatHistories, CharacterHistories, LogHistories, UnsignedHistories
+// and TextHistories, as returned by ItemHistoriesGet.
+type TypedHistories interface {
+	ValueType() ValueType
+	Len() int
+}
+
+func (h FloatHistories) ValueType() ValueType     { return Float }
+func (h CharacterHistories) ValueType() ValueType { return Character }
+func (h LogHistories) ValueType() ValueType       { return Log }
+func (h UnsignedHistories) ValueType() ValueType  { return Unsigned }
+func (h TextHistories) ValueType() ValueType      { return Text }
+
+func (h FloatHistories) Len() int     { return len(h) }
+func (h CharacterHistories) Len() int { return len(h) }
+func (h LogHistories) Len() int       { return len(h) }
+func (h UnsignedHistories) Len() int  { return len(h) }
+func (h TextHistories) Len() int      { return len(h) }
+
+// ItemHistoriesGet gets history of item typed by item.ValueType, for example, FloatHistories for Float items.
+// params may be nil.
+func (api *API) ItemHistoriesGet(item *Item, params Params) (res TypedHistories, err error) {
+	if params == nil {
+		params = Params{}
+	}
+	params["itemids"] = item.ItemId
+	switch item.ValueType {
+	case Float:
+		return api.FloatHistoriesGet(params)
+	case Character:
+		return api.CharacterHistoriesGet(params)
+	case Log:
+		return api.LogHistoriesGet(params)
+	case Unsigned:
+		return api.UnsignedHistoriesGet(params)
+	case Text:
+		return api.TextHistoriesGet(params)
+	}
+	return nil, fmt.Errorf("Unexpected value type %d of item %s.", item.ValueType, item.ItemId)
+}
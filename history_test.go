@@ -0,0 +1,116 @@
+package zabbix_test
+
+import (
+	"testing"
+
+	. "."
+)
+
+func TestHistories(t *testing.T) {
+	api := getAPI(t)
+
+	group := CreateHostGroup(t)
+	defer DeleteHostGroup(group, t)
+
+	host := CreateHost(group, t)
+	defer DeleteHost(host, t)
+
+	app := CreateApplication(host, t)
+	defer DeleteApplication(app, t)
+
+	item := CreateItem(app, t)
+	defer DeleteItem(item, t)
+
+	_, err := api.HistoriesGet(Params{"itemids": item.ItemId})
+	if err == nil {
+		t.Error("Expected error without history parameter")
+	}
+
+	items, err := api.ItemsGet(Params{"itemids": item.ItemId})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items[0].ValueType != item.ValueType {
+		t.Fatalf("Bad items: %#v", items)
+	}
+
+	histories, err := api.ItemHistoriesGet(&items[0], nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if histories.ValueType() != item.ValueType || histories.Len() != 0 {
+		t.Errorf("Bad histories: %#v", histories)
+	}
+
+	floats, err := api.FloatHistoriesGet(Params{"itemids": item.ItemId})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(floats) != 0 {
+		t.Errorf("Bad histories: %#v", floats)
+	}
+}
+
+func TestHistoryValues(t *testing.T) {
+	h := History{Value: "42.5"}
+	f, err := h.Float()
+	if err != nil || f != 42.5 {
+		t.Errorf("Bad float: %v %v", f, err)
+	}
+
+	h = History{Value: "18446744073709551615"}
+	u, err := h.Unsigned()
+	if err != nil || u != 18446744073709551615 {
+		t.Errorf("Bad unsigned: %v %v", u, err)
+	}
+}
+
+func TestItemHistoriesGet(t *testing.T) {
+	for _, c := range []struct {
+		valueType ValueType
+		result    string
+		check     func(TypedHistories) bool
+	}{
+		{Float, `[{"itemid": "1", "clock": "1600000000", "ns": "5", "value": "42.5"}]`, func(h TypedHistories) bool {
+			v, ok := h.(FloatHistories)
+			return ok && v[0].Value == 42.5 && v[0].Clock == 1600000000 && v[0].Ns == 5
+		}},
+		{Character, `[{"itemid": "1", "clock": "1600000000", "ns": "0", "value": "up"}]`, func(h TypedHistories) bool {
+			v, ok := h.(CharacterHistories)
+			return ok && v[0].Value == "up"
+		}},
+		{Log, `[{"id": "7", "itemid": "1", "clock": "1600000000", "ns": "0", "value": "disk full", "logeventid": "3", "severity": "4", "source": "kernel", "timestamp": "1599999999"}]`, func(h TypedHistories) bool {
+			v, ok := h.(LogHistories)
+			return ok && v[0].Value == "disk full" && v[0].LogEventId == 3 && v[0].Severity == 4 && v[0].Source == "kernel" && v[0].Timestamp == 1599999999
+		}},
+		{Unsigned, `[{"itemid": "1", "clock": "1600000000", "ns": "0", "value": "18446744073709551615"}]`, func(h TypedHistories) bool {
+			v, ok := h.(UnsignedHistories)
+			return ok && v[0].Value == 18446744073709551615
+		}},
+		{Text, `[{"id": "8", "itemid": "1", "clock": "1600000000", "ns": "0", "value": "line 1\nline 2"}]`, func(h TypedHistories) bool {
+			v, ok := h.(TextHistories)
+			return ok && v[0].Id == "8" && v[0].Value == "line 1\nline 2"
+		}},
+	} {
+		api, requests := fakeAPI(t, map[string]string{"history.get": c.result})
+		h, err := api.ItemHistoriesGet(&Item{ItemId: "1", ValueType: c.valueType}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		params := (*requests)[0]["params"].(map[string]interface{})
+		if params["history"] != float64(c.valueType) || params["itemids"] != "1" {
+			t.Errorf("Unexpected params %v", params)
+		}
+		if h.ValueType() != c.valueType || h.Len() != 1 || !c.check(h) {
+			t.Errorf("Unexpected histories of value type %d: %#v", c.valueType, h)
+		}
+	}
+
+	api, _ := fakeAPI(t, nil)
+	if _, err := api.ItemHistoriesGet(&Item{ItemId: "1", ValueType: 9}, nil); err == nil {
+		t.Error("Expected error for unexpected value type")
+	}
+	if _, err := api.FloatHistoriesGet(nil); err != nil {
+		t.Error(err)
+	}
+}
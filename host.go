@@ -1,7 +1,5 @@
 package zabbix
 
-import "github.com/mitchellh/mapstructure"
-
 type (
 	AvailableType int
 	StatusType    int
@@ -37,7 +35,7 @@ type HostId struct {
 	HostId string `json:"hostid"`
 }
 
-//type HostIDs []HostID
+type HostIds []HostId
 
 type Hosts []Host
 
@@ -51,8 +49,7 @@ func (api *API) HostsGet(params Params) (res Hosts, err error) {
 		return
 	}
 
-	mapstructure.Decode(response.Result.([]interface{}), &res)
-
+	err = decode(response.Result.([]interface{}), &res)
 	return
 }
 
@@ -117,6 +114,22 @@ func (api *API) HostsCreate(hosts Hosts) (err error) {
 	return
 }
 
+// Wrapper for host.update: https://www.zabbix.com/documentation/2.2/manual/appendix/api/host/update
+// Non-empty GroupIds, Interfaces and TemplateIds replace existing ones.
+func (api *API) HostsUpdate(hosts Hosts) (err error) {
+	response, err := api.CallWithError("host.update", hosts)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	hostids := result["hostids"].([]interface{})
+	if len(hosts) != len(hostids) {
+		err = &ExpectedMore{len(hosts), len(hostids)}
+	}
+	return
+}
+
 // Wrapper for host.delete: https://www.zabbix.com/documentation/2.2/manual/appendix/api/host/delete
 // Cleans HostId in all hosts elements if call succeed.
 func (api *API) HostsDelete(hosts Hosts) (err error) {
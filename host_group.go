@@ -1,7 +1,5 @@
 package zabbix
 
-import "github.com/mitchellh/mapstructure"
-
 type (
 	InternalType int
 )
@@ -36,8 +34,7 @@ func (api *API) HostGroupsGet(params Params) (res HostGroups, err error) {
 		return
 	}
 
-	mapstructure.Decode(response.Result.([]interface{}), &res)
-
+	err = decode(response.Result.([]interface{}), &res)
 	return
 }
 
@@ -13,12 +13,28 @@ const (
 
 // https://www.zabbix.com/documentation/2.2/manual/appendix/api/hostinterface/definitions
 type HostInterface struct {
-	DNS   string        `json:"dns"`
-	IP    string        `json:"ip"`
-	Main  int           `json:"main"`
-	Port  string        `json:"port"`
-	Type  InterfaceType `json:"type"`
-	UseIP int           `json:"useip"`
+	InterfaceId string        `json:"interfaceid,omitempty"`
+	HostId      string        `json:"hostid,omitempty"`
+	DNS         string        `json:"dns"`
+	IP          string        `json:"ip"`
+	Main        int           `json:"main"`
+	Port        string        `json:"port"`
+	Type        InterfaceType `json:"type"`
+	UseIP       int           `json:"useip"`
 }
 
 type HostInterfaces []HostInterface
+
+// Wrapper for hostinterface.get: https://www.zabbix.com/documentation/2.2/manual/api/reference/hostinterface/get
+func (api *API) HostInterfacesGet(params Params) (res HostInterfaces, err error) {
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	response, err := api.CallWithError("hostinterface.get", params)
+	if err != nil {
+		return
+	}
+
+	err = decode(response.Result.([]interface{}), &res)
+	return
+}
@@ -1,10 +1,6 @@
 package zabbix
 
-import (
-	"fmt"
-
-	"github.com/mitchellh/mapstructure"
-)
+import "fmt"
 
 type (
 	ItemType  int
@@ -51,7 +47,7 @@ const (
 // https://www.zabbix.com/documentation/2.2/manual/appendix/api/item/definitions
 type Item struct {
 	ItemId      string    `json:"itemid,omitempty"`
-	Delay       int       `json:"delay"`
+	Delay       string    `json:"delay,omitempty"` // seconds before Zabbix 3.4, time unit like "1m" or macro since
 	HostId      string    `json:"hostid"`
 	InterfaceId string    `json:"interfaceid,omitempty"`
 	Key         string    `json:"key_"`
@@ -61,9 +57,9 @@ type Item struct {
 	DataType    DataType  `json:"data_type"`
 	Delta       DeltaType `json:"delta"`
 	Description string    `json:"description"`
-	Error       string    `json:"error"`
-	History     int       `json:"history,omitempty"`
-	Trends      int       `json:"trends,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	History     string    `json:"history,omitempty"` // days before Zabbix 3.4, time unit like "90d" since
+	Trends      string    `json:"trends,omitempty"`  // days before Zabbix 3.4, time unit like "365d" since
 	TriggersIds []string  `json:"triggers,omitempty"`
 
 	// Fields below used only when creating applications
@@ -95,8 +91,8 @@ func (api *API) ItemsGet(params Params) (res Items, err error) {
 		return
 	}
 
-	mapstructure.Decode(response.Result.([]interface{}), &res)
-
+	// json tags are required to decode value_type, used by ItemHistoriesGet
+	err = decode(response.Result.([]interface{}), &res)
 	return
 }
 
@@ -125,6 +121,21 @@ func (api *API) ItemsCreate(items Items) (err error) {
 	return
 }
 
+// Wrapper for item.update: https://www.zabbix.com/documentation/2.2/manual/appendix/api/item/update
+func (api *API) ItemsUpdate(items Items) (err error) {
+	response, err := api.CallWithError("item.update", items)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	itemids := result["itemids"].([]interface{})
+	if len(items) != len(itemids) {
+		err = &ExpectedMore{len(items), len(itemids)}
+	}
+	return
+}
+
 // Wrapper for item.delete: https://www.zabbix.com/documentation/2.2/manual/appendix/api/item/delete
 // Cleans ItemId in all items elements if call succeed.
 func (api *API) ItemsDelete(items Items) (err error) {
@@ -51,3 +51,22 @@ func TestItems(t *testing.T) {
 	item := CreateItem(app, t)
 	DeleteItem(item, t)
 }
+
+func TestItemsGetIntervals(t *testing.T) {
+	for _, c := range []struct {
+		result                 string
+		delay, history, trends string
+	}{
+		{`[{"itemid": "1", "key_": "agent.ping", "type": "0", "value_type": "3", "delay": "60", "history": "90", "trends": "365"}]`, "60", "90", "365"},
+		{`[{"itemid": "1", "key_": "agent.ping", "type": "0", "value_type": "3", "delay": "1m", "history": "90d", "trends": "365d"}]`, "1m", "90d", "365d"},
+	} {
+		api, _ := fakeAPI(t, map[string]string{"item.get": c.result})
+		items, err := api.ItemsGet(Params{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i := items[0]; i.ValueType != Unsigned || i.Delay != c.delay || i.History != c.history || i.Trends != c.trends {
+			t.Errorf("Unexpected item %#v", i)
+		}
+	}
+}
@@ -1,12 +1,15 @@
 package zabbix
 
 import (
-	"github.com/mitchellh/mapstructure"
+	"encoding/json"
+	"fmt"
 )
 
 type (
-	MaintType  int
-	PeriodType int
+	MaintType       int
+	PeriodType      int
+	TagOperatorType int
+	TagsEvalType    int
 )
 
 const (
@@ -17,11 +20,17 @@ const (
 	Daily   PeriodType = 2
 	Weekly  PeriodType = 3
 	Monthly PeriodType = 4
+
+	TagEquals   TagOperatorType = 0
+	TagContains TagOperatorType = 2
+
+	TagsAndOr TagsEvalType = 0 // AND between different tags, OR between conditions for the same tag
+	TagsOr    TagsEvalType = 2
 )
 
 // Maintenance struct - https://www.zabbix.com/documentation/2.4/manual/api/reference/maintenance/object#maintenance
 type Maintenance struct {
-	MaintenanceID   string      `json:"maintenanceid,omitepmty"`
+	MaintenanceID   string      `json:"maintenanceid,omitempty"`
 	Name            string      `json:"name"`
 	ActiveSince     int64       `json:"active_since"`
 	ActiveTill      int64       `json:"active_till"`
@@ -32,11 +41,37 @@ type Maintenance struct {
 	Hosts           Hosts       `json:"hosts,omitempty"`
 	HostGroups      HostGroups  `json:"groups,omitempty"`
 	TimePeriods     TimePeriods `json:"timeperiods,omitempty"`
+
+	// Fields below require Zabbix 4.0+. Without tags all problems of hosts are suppressed.
+	// TagsEvalType is sent only with tags, so maintenances without tags can be used with older versions.
+	Tags         MaintenanceTags `json:"tags,omitempty"`
+	TagsEvalType TagsEvalType    `json:"tags_evaltype"`
+}
+
+type maintenanceNoMethods Maintenance
+
+func (m Maintenance) MarshalJSON() ([]byte, error) {
+	if len(m.Tags) > 0 {
+		return json.Marshal(maintenanceNoMethods(m))
+	}
+	return json.Marshal(struct {
+		maintenanceNoMethods
+		TagsEvalType *TagsEvalType `json:"tags_evaltype,omitempty"` // hides embedded field
+	}{maintenanceNoMethods: maintenanceNoMethods(m)})
+}
+
+// MaintenanceTag is a condition on problem tags - https://www.zabbix.com/documentation/4.0/manual/api/reference/maintenance/object#problem_tag
+type MaintenanceTag struct {
+	Tag      string          `json:"tag"`
+	Operator TagOperatorType `json:"operator"`
+	Value    string          `json:"value,omitempty"`
 }
 
+type MaintenanceTags []MaintenanceTag
+
 // TimePeriod struct - https://www.zabbix.com/documentation/2.4/manual/api/reference/maintenance/object#time_period
 type TimePeriod struct {
-	TimePeriodID   string     `json:"timeperiodid"`
+	TimePeriodID   string     `json:"timeperiodid,omitempty"`
 	Day            string     `json:"day,omitempty"`
 	DayOfWeek      int        `json:"dayofweek,omitempty"`
 	Every          int        `json:"every,omitempty"`
@@ -53,8 +88,28 @@ type Maintenances []Maintenance
 // TimePeriods slice struct for storing result returned from get method
 type TimePeriods []TimePeriod
 
+// Validate checks that tags are used only with data collection, as Zabbix does.
+func (m *Maintenance) Validate() error {
+	if len(m.Tags) > 0 && m.MaintenanceType != WithData {
+		return fmt.Errorf("Maintenance %q without data collection can't have tags.", m.Name)
+	}
+	for _, t := range m.Tags {
+		if t.Tag == "" {
+			return fmt.Errorf("Maintenance %q has tag condition without tag name.", m.Name)
+		}
+		if t.Operator != TagEquals && t.Operator != TagContains {
+			return fmt.Errorf("Maintenance %q has tag %q with unexpected operator %d.", m.Name, t.Tag, t.Operator)
+		}
+	}
+	if m.TagsEvalType != TagsAndOr && m.TagsEvalType != TagsOr {
+		return fmt.Errorf("Maintenance %q has unexpected tags evaluation type %d.", m.Name, m.TagsEvalType)
+	}
+	return nil
+}
+
 // MaintenancesGet returns all available maintenances according to given parameters -
 // https://www.zabbix.com/documentation/2.4/manual/api/reference/maintenance/get
+// Hosts, groups, time periods and, on Zabbix 4.0+, tags are selected by default.
 func (api *API) MaintenancesGet(params Params) (res Maintenances, err error) {
 	if _, present := params["output"]; !present {
 		params["output"] = "extend"
@@ -68,29 +123,20 @@ func (api *API) MaintenancesGet(params Params) (res Maintenances, err error) {
 	if _, present := params["selectTimeperiods"]; !present {
 		params["selectTimeperiods"] = "extend"
 	}
+	if _, present := params["selectTags"]; !present {
+		var tags bool
+		if tags, err = api.versionAtLeast(4, 0); err != nil {
+			return
+		}
+		if tags {
+			params["selectTags"] = "extend"
+		}
+	}
 	response, err := api.CallWithError("maintenance.get", params)
 	if err != nil {
 		return
 	}
-	res = make(Maintenances, len(response.Result.([]interface{})))
-	for i, h := range response.Result.([]interface{}) {
-		h2 := h.(map[string]interface{})
-		mapstructure.Decode(h2, &res[i])
-
-		if hosts, ok := h2["hosts"]; ok {
-			mapstructure.Decode(hosts.([]interface{}), &res[i].Hosts)
-
-		}
-		if hostgroups, ok := h2["groups"]; ok {
-			mapstructure.Decode(hostgroups.([]interface{}), &res[i].HostGroups)
-		}
-		if timeperiods, ok := h2["timeperiods"]; ok {
-			mapstructure.Decode(timeperiods.([]interface{}), &res[i].TimePeriods)
-
-		}
-
-	}
-
+	err = decode(response.Result.([]interface{}), &res)
 	return
 }
 
@@ -127,6 +173,11 @@ func (api *API) MaintenanceGetByName(name string) (res Maintenance, err error) {
 
 // MaintenancesCreate creates maintenances using maintenance.create - https://www.zabbix.com/documentation/2.4/manual/api/reference/maintenance/create
 func (api *API) MaintenancesCreate(maintenances Maintenances) (err error) {
+	for i := range maintenances {
+		if err = maintenances[i].Validate(); err != nil {
+			return
+		}
+	}
 	response, err := api.CallWithError("maintenance.create", maintenances)
 	if err != nil {
 		return
@@ -145,6 +196,9 @@ func (api *API) MaintenancesCreate(maintenances Maintenances) (err error) {
 func (api *API) MaintenancesUpdate(maintenances Maintenances) (err error) {
 	ids := make([]string, len(maintenances))
 	for i, maintenance := range maintenances {
+		if err = maintenance.Validate(); err != nil {
+			return
+		}
 		ids[i] = maintenance.MaintenanceID
 	}
 	response, err := api.CallWithError("maintenance.update", maintenances)
@@ -0,0 +1,476 @@
+package zabbix
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	weekdayNames = []string{"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"} // DayOfWeek bits order
+	monthNames   = []string{"january", "february", "march", "april", "may", "june", "july", "august", "september", "october", "november", "december"}
+	weekNames    = []string{"", "first", "second", "third", "fourth", "last"} // by Every of monthly time period
+
+	dayOfMonthRE = regexp.MustCompile(`^(\d+)(st|nd|rd|th)?$`)
+	clockRE      = regexp.MustCompile(`^(\d{1,2}):(\d{2})$`)
+)
+
+// Parses comma-separated list of names or their three-letter abbreviations into bitmask.
+func parseNames(s string, names []string) (mask int, err error) {
+	for _, part := range strings.Split(s, ",") {
+		found := false
+		for i, name := range names {
+			if part == name || (len(part) == 3 && strings.HasPrefix(name, part)) {
+				mask |= 1 << uint(i)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return 0, fmt.Errorf("unexpected %q", part)
+		}
+	}
+	return
+}
+
+// Formats bitmask as comma-separated list of capitalized names, abbreviated if short is true.
+func formatNames(mask int, names []string, short bool) string {
+	var res []string
+	for i, name := range names {
+		if mask&(1<<uint(i)) != 0 {
+			if short {
+				name = name[:3]
+			}
+			res = append(res, strings.ToUpper(name[:1])+name[1:])
+		}
+	}
+	return strings.Join(res, ",")
+}
+
+// Parses duration like "2h", "90m" or "1d12h".
+func parseDuration(s string) (res time.Duration, err error) {
+	if i := strings.Index(s, "d"); i > 0 {
+		days, err := strconv.Atoi(s[:i])
+		if err != nil {
+			return 0, fmt.Errorf("unexpected duration %q", s)
+		}
+		res, s = time.Duration(days)*24*time.Hour, s[i+1:]
+		if s == "" {
+			return res, nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("unexpected duration %q", s)
+	}
+	return res + d, nil
+}
+
+// Formats duration in seconds like "1d12h" or "2h30m".
+func formatDuration(seconds int64) (res string) {
+	for _, u := range []struct {
+		name    string
+		seconds int64
+	}{{"d", 86400}, {"h", 3600}, {"m", 60}, {"s", 1}} {
+		if n := seconds / u.seconds; n > 0 {
+			res += strconv.FormatInt(n, 10) + u.name
+			seconds -= n * u.seconds
+		}
+	}
+	if res == "" {
+		res = "0s"
+	}
+	return
+}
+
+// timePeriodParser consumes lowercase words of time period expression.
+type timePeriodParser struct {
+	words []string
+}
+
+func (p *timePeriodParser) peek() string {
+	if len(p.words) == 0 {
+		return ""
+	}
+	return p.words[0]
+}
+
+func (p *timePeriodParser) next() (w string) {
+	w = p.peek()
+	if len(p.words) > 0 {
+		p.words = p.words[1:]
+	}
+	return
+}
+
+func (p *timePeriodParser) skip(word string) bool {
+	if p.peek() == word {
+		p.next()
+		return true
+	}
+	return false
+}
+
+func (p *timePeriodParser) expect(word string) error {
+	if w := p.next(); w != word {
+		return fmt.Errorf("expected %q, got %q", word, w)
+	}
+	return nil
+}
+
+// Parses "of every month" or "of jan,jul".
+func (p *timePeriodParser) months() (mask int, err error) {
+	if err = p.expect("of"); err != nil {
+		return
+	}
+	if p.skip("every") {
+		return 1<<12 - 1, p.expect("month")
+	}
+	return parseNames(p.next(), monthNames)
+}
+
+// Parses "[at] 22:00 for 4h".
+func (p *timePeriodParser) clock() (startTime int64, period time.Duration, err error) {
+	p.skip("at")
+	m := clockRE.FindStringSubmatch(p.next())
+	if m == nil {
+		return 0, 0, fmt.Errorf("expected time like 22:00")
+	}
+	h, _ := strconv.Atoi(m[1])
+	min, _ := strconv.Atoi(m[2])
+	if h > 23 || min > 59 {
+		return 0, 0, fmt.Errorf("unexpected time %s", m[0])
+	}
+	if err = p.expect("for"); err != nil {
+		return
+	}
+	period, err = parseDuration(p.next())
+	return int64(h*3600 + min*60), period, err
+}
+
+// ParseTimePeriod parses human-friendly time period expressions:
+//
+//	every day at 02:00 for 2h
+//	every 2 days at 02:00 for 30m
+//	every Sunday 02:00 for 2h
+//	every 2 weeks on Mon,Thu at 01:00 for 1h
+//	first Monday of Jan,Jul at 22:00 for 4h
+//	last Friday of every month at 18:00 for 1d
+//	day 15 of every month at 00:00 for 1h30m
+//	once 2021-03-10 12:00 for 1h
+//
+// Days of week and months may be abbreviated, "at" is optional, time of day is in location of Zabbix server.
+// Location loc is used only for dates of one-time periods. Result is validated.
+func ParseTimePeriod(s string, loc *time.Location) (res TimePeriod, err error) {
+	s = strings.ToLower(strings.Join(strings.Fields(s), " "))
+	s = strings.Replace(s, ", ", ",", -1)
+	p := &timePeriodParser{words: strings.Fields(s)}
+
+	err = p.parse(&res, loc)
+	if err == nil && len(p.words) > 0 {
+		err = fmt.Errorf("unexpected %q", strings.Join(p.words, " "))
+	}
+	if err == nil {
+		err = res.Validate()
+	}
+	if err != nil {
+		err = fmt.Errorf("Can't parse time period %q: %s.", s, err)
+	}
+	return
+}
+
+func (p *timePeriodParser) parse(res *TimePeriod, loc *time.Location) (err error) {
+	var period time.Duration
+	word := p.next()
+	switch {
+	case word == "once":
+		var start time.Time
+		if start, err = time.ParseInLocation("2006-01-02 15:04", p.next()+" "+p.next(), loc); err != nil {
+			return
+		}
+		if err = p.expect("for"); err != nil {
+			return
+		}
+		res.TimePeriodType, res.StartDate = OneTime, start.Unix()
+		period, err = parseDuration(p.next())
+
+	case word == "every":
+		res.Every = 1
+		if n, err := strconv.Atoi(p.peek()); err == nil {
+			p.next()
+			res.Every = n
+		}
+		switch unit := p.peek(); {
+		case unit == "day" || unit == "days":
+			p.next()
+			res.TimePeriodType = Daily
+		case unit == "week" || unit == "weeks":
+			p.next()
+			if err = p.expect("on"); err != nil {
+				return
+			}
+			fallthrough
+		default:
+			res.TimePeriodType = Weekly
+			if res.DayOfWeek, err = parseNames(p.next(), weekdayNames); err != nil {
+				return
+			}
+		}
+		res.StartTime, period, err = p.clock()
+
+	case word == "day" || dayOfMonthRE.MatchString(word):
+		if word == "day" {
+			word = p.next()
+		}
+		m := dayOfMonthRE.FindStringSubmatch(word)
+		if m == nil {
+			return fmt.Errorf("expected day of month, got %q", word)
+		}
+		res.TimePeriodType, res.Day = Monthly, m[1]
+		if res.Month, err = p.months(); err != nil {
+			return
+		}
+		res.StartTime, period, err = p.clock()
+
+	default:
+		for i, name := range weekNames {
+			if i > 0 && name == word {
+				res.Every = i
+			}
+		}
+		if res.Every == 0 {
+			return fmt.Errorf("unexpected %q", word)
+		}
+		res.TimePeriodType = Monthly
+		if res.DayOfWeek, err = parseNames(p.next(), weekdayNames); err != nil {
+			return
+		}
+		if res.Month, err = p.months(); err != nil {
+			return
+		}
+		res.StartTime, period, err = p.clock()
+	}
+
+	res.Period = int64(period / time.Second)
+	return
+}
+
+// Validate checks fields of time period like Zabbix server does.
+func (p *TimePeriod) Validate() error {
+	if p.Period < 300 {
+		return fmt.Errorf("period should be at least 5 minutes, got %s", formatDuration(p.Period))
+	}
+	if p.TimePeriodType == OneTime {
+		if p.StartDate <= 0 {
+			return fmt.Errorf("start date is not set")
+		}
+		return nil
+	}
+	if p.StartTime < 0 || p.StartTime >= 86400 {
+		return fmt.Errorf("start time should be within a day, got %d seconds", p.StartTime)
+	}
+
+	switch p.TimePeriodType {
+	case Daily:
+		if p.Every < 1 {
+			return fmt.Errorf("every should be positive, got %d", p.Every)
+		}
+	case Weekly:
+		if p.Every < 1 {
+			return fmt.Errorf("every should be positive, got %d", p.Every)
+		}
+		if p.DayOfWeek < 1 || p.DayOfWeek > 127 {
+			return fmt.Errorf("unexpected days of week %d", p.DayOfWeek)
+		}
+	case Monthly:
+		if p.Month < 1 || p.Month > 4095 {
+			return fmt.Errorf("unexpected months %d", p.Month)
+		}
+		day, _ := strconv.Atoi(p.Day)
+		switch {
+		case day != 0:
+			if day < 1 || day > 31 {
+				return fmt.Errorf("unexpected day of month %s", p.Day)
+			}
+		case p.Every < FirstWeek || p.Every > LastWeek:
+			return fmt.Errorf("unexpected week of month %d", p.Every)
+		case p.DayOfWeek < 1 || p.DayOfWeek > 127:
+			return fmt.Errorf("unexpected days of week %d", p.DayOfWeek)
+		}
+	default:
+		return fmt.Errorf("unexpected time period type %d", p.TimePeriodType)
+	}
+	return nil
+}
+
+// Describe renders time period as expression accepted by ParseTimePeriod, for example,
+// "first Monday of Jan,Jul at 22:00 for 4h". Location loc is used only for dates of one-time periods.
+func (p *TimePeriod) Describe(loc *time.Location) string {
+	clock := fmt.Sprintf("at %02d:%02d for %s", p.StartTime/3600, p.StartTime%3600/60, formatDuration(p.Period))
+	months := "every month"
+	if p.Month != 1<<12-1 {
+		months = formatNames(p.Month, monthNames, true)
+	}
+
+	switch p.TimePeriodType {
+	case OneTime:
+		return fmt.Sprintf("once %s for %s", time.Unix(p.StartDate, 0).In(loc).Format("2006-01-02 15:04"), formatDuration(p.Period))
+	case Daily:
+		if p.Every > 1 {
+			return fmt.Sprintf("every %d days %s", p.Every, clock)
+		}
+		return "every day " + clock
+	case Weekly:
+		if p.Every > 1 {
+			return fmt.Sprintf("every %d weeks on %s %s", p.Every, formatNames(p.DayOfWeek, weekdayNames, false), clock)
+		}
+		return fmt.Sprintf("every %s %s", formatNames(p.DayOfWeek, weekdayNames, false), clock)
+	case Monthly:
+		if day, _ := strconv.Atoi(p.Day); day != 0 {
+			return fmt.Sprintf("day %d of %s %s", day, months, clock)
+		}
+		week := ""
+		if p.Every >= FirstWeek && p.Every <= LastWeek {
+			week = weekNames[p.Every]
+		}
+		return fmt.Sprintf("%s %s of %s %s", week, formatNames(p.DayOfWeek, weekdayNames, false), months, clock)
+	}
+	return fmt.Sprintf("unknown time period type %d", p.TimePeriodType)
+}
+
+// MaintenanceBuilder builds Maintenance step by step:
+//
+//	m, err := NewMaintenanceBuilder("Patching").
+//		Hosts(hostId).
+//		Schedule("every Sunday 02:00 for 2h").
+//		Active(since, till).
+//		Build()
+//
+// Errors are collected and returned by Build.
+type MaintenanceBuilder struct {
+	m   Maintenance
+	loc *time.Location
+	err error
+}
+
+// NewMaintenanceBuilder returns builder of maintenance with data collection and time zone time.Local.
+func NewMaintenanceBuilder(name string) *MaintenanceBuilder {
+	return &MaintenanceBuilder{m: Maintenance{Name: name, MaintenanceType: WithData}, loc: time.Local}
+}
+
+func (b *MaintenanceBuilder) fail(err error) *MaintenanceBuilder {
+	if b.err == nil {
+		b.err = err
+	}
+	return b
+}
+
+// Location sets time zone of Zabbix server used for dates of one-time periods in Schedule expressions.
+func (b *MaintenanceBuilder) Location(loc *time.Location) *MaintenanceBuilder {
+	b.loc = loc
+	return b
+}
+
+func (b *MaintenanceBuilder) Description(description string) *MaintenanceBuilder {
+	b.m.Description = description
+	return b
+}
+
+// WithoutData disables data collection during maintenance.
+func (b *MaintenanceBuilder) WithoutData() *MaintenanceBuilder {
+	b.m.MaintenanceType = WithoutData
+	return b
+}
+
+// Hosts adds hosts by IDs.
+func (b *MaintenanceBuilder) Hosts(ids ...string) *MaintenanceBuilder {
+	b.m.HostIDs = append(b.m.HostIDs, ids...)
+	return b
+}
+
+// Groups adds host groups by IDs.
+func (b *MaintenanceBuilder) Groups(ids ...string) *MaintenanceBuilder {
+	b.m.HostGroupIDs = append(b.m.HostGroupIDs, ids...)
+	return b
+}
+
+// Tag adds condition on problem tags: only matching problems are suppressed.
+func (b *MaintenanceBuilder) Tag(tag string, operator TagOperatorType, value string) *MaintenanceBuilder {
+	b.m.Tags = append(b.m.Tags, MaintenanceTag{Tag: tag, Operator: operator, Value: value})
+	return b
+}
+
+// AnyTag makes problems match if any tag condition matches, instead of all conditions for different tags.
+func (b *MaintenanceBuilder) AnyTag() *MaintenanceBuilder {
+	b.m.TagsEvalType = TagsOr
+	return b
+}
+
+// Active sets period when maintenance schedules are in effect.
+func (b *MaintenanceBuilder) Active(since, till time.Time) *MaintenanceBuilder {
+	if !till.After(since) {
+		return b.fail(fmt.Errorf("Active till %s is not after since %s.", till, since))
+	}
+	b.m.ActiveSince, b.m.ActiveTill = since.Unix(), till.Unix()
+	return b
+}
+
+// Schedule adds time period described by expression, see ParseTimePeriod.
+func (b *MaintenanceBuilder) Schedule(expression string) *MaintenanceBuilder {
+	p, err := ParseTimePeriod(expression, b.loc)
+	if err != nil {
+		return b.fail(err)
+	}
+	return b.TimePeriod(p)
+}
+
+// Once adds one-time period [start, end).
+func (b *MaintenanceBuilder) Once(start, end time.Time) *MaintenanceBuilder {
+	return b.TimePeriod(TimePeriod{TimePeriodType: OneTime, StartDate: start.Unix(), Period: int64(end.Sub(start) / time.Second)})
+}
+
+// TimePeriod adds time period after validation.
+func (b *MaintenanceBuilder) TimePeriod(p TimePeriod) *MaintenanceBuilder {
+	if err := p.Validate(); err != nil {
+		return b.fail(fmt.Errorf("Invalid time period: %s.", err))
+	}
+	b.m.TimePeriods = append(b.m.TimePeriods, p)
+	return b
+}
+
+// Build returns maintenance or first error.
+// If active period is not set and there are only one-time periods, it is set to cover them.
+func (b *MaintenanceBuilder) Build() (res Maintenance, err error) {
+	if b.err != nil {
+		return res, b.err
+	}
+	m := b.m
+	switch {
+	case m.Name == "":
+		return res, fmt.Errorf("Maintenance name is not set.")
+	case len(m.HostIDs) == 0 && len(m.HostGroupIDs) == 0:
+		return res, fmt.Errorf("Maintenance %q has no hosts and host groups.", m.Name)
+	case len(m.TimePeriods) == 0:
+		return res, fmt.Errorf("Maintenance %q has no time periods.", m.Name)
+	}
+	if err = m.Validate(); err != nil {
+		return
+	}
+
+	if m.ActiveSince == 0 && m.ActiveTill == 0 {
+		for _, p := range m.TimePeriods {
+			if p.TimePeriodType != OneTime {
+				return res, fmt.Errorf("Maintenance %q has recurring time periods, active period should be set.", m.Name)
+			}
+			if m.ActiveSince == 0 || p.StartDate < m.ActiveSince {
+				m.ActiveSince = p.StartDate
+			}
+			if end := p.StartDate + p.Period; end > m.ActiveTill {
+				m.ActiveTill = end
+			}
+		}
+	}
+	return m, nil
+}
@@ -0,0 +1,162 @@
+package zabbix_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	. "."
+)
+
+func TestParseTimePeriod(t *testing.T) {
+	loc := time.FixedZone("UTC+3", 3*3600)
+
+	for s, expected := range map[string]struct {
+		period      TimePeriod
+		description string
+	}{
+		"every day at 02:00 for 2h": {
+			TimePeriod{TimePeriodType: Daily, Every: 1, StartTime: 7200, Period: 7200},
+			"every day at 02:00 for 2h",
+		},
+		"Every 3 days 23:30 for 1h30m": {
+			TimePeriod{TimePeriodType: Daily, Every: 3, StartTime: 23*3600 + 1800, Period: 5400},
+			"every 3 days at 23:30 for 1h30m",
+		},
+		"every Sunday 02:00 for 2h": {
+			TimePeriod{TimePeriodType: Weekly, Every: 1, DayOfWeek: 64, StartTime: 7200, Period: 7200},
+			"every Sunday at 02:00 for 2h",
+		},
+		"every 2 weeks on Mon, thu at 1:05 for 1d": {
+			TimePeriod{TimePeriodType: Weekly, Every: 2, DayOfWeek: 1 | 8, StartTime: 3900, Period: 86400},
+			"every 2 weeks on Monday,Thursday at 01:05 for 1d",
+		},
+		"first Monday of Jan,Jul at 22:00 for 4h": {
+			TimePeriod{TimePeriodType: Monthly, Every: FirstWeek, DayOfWeek: 1, Month: 1 | 64, StartTime: 22 * 3600, Period: 4 * 3600},
+			"first Monday of Jan,Jul at 22:00 for 4h",
+		},
+		"last friday of every month at 18:00 for 1d12h": {
+			TimePeriod{TimePeriodType: Monthly, Every: LastWeek, DayOfWeek: 16, Month: 4095, StartTime: 18 * 3600, Period: 86400 + 12*3600},
+			"last Friday of every month at 18:00 for 1d12h",
+		},
+		"15th of february at 00:00 for 30m": {
+			TimePeriod{TimePeriodType: Monthly, Day: "15", Month: 2, Period: 1800},
+			"day 15 of Feb at 00:00 for 30m",
+		},
+		"once 2021-03-10 12:00 for 1h": {
+			TimePeriod{TimePeriodType: OneTime, StartDate: time.Date(2021, 3, 10, 9, 0, 0, 0, time.UTC).Unix(), Period: 3600},
+			"once 2021-03-10 12:00 for 1h",
+		},
+	} {
+		p, err := ParseTimePeriod(s, loc)
+		if err != nil {
+			t.Errorf("%s: %s", s, err)
+			continue
+		}
+		if !reflect.DeepEqual(p, expected.period) {
+			t.Errorf("%s: expected %#v, got %#v", s, expected.period, p)
+		}
+		d := p.Describe(loc)
+		if d != expected.description {
+			t.Errorf("%s: expected description %q, got %q", s, expected.description, d)
+		}
+		if p2, err := ParseTimePeriod(d, loc); err != nil || !reflect.DeepEqual(p, p2) {
+			t.Errorf("%s: description %q is not parsed back: %#v, %v", s, d, p2, err)
+		}
+	}
+
+	for _, s := range []string{
+		"",
+		"every day at 02:00",
+		"every day at 25:00 for 1h",
+		"every day at 02:00 for 1m",
+		"every funday at 02:00 for 1h",
+		"fifth Monday of Jan at 02:00 for 1h",
+		"day 32 of Jan at 02:00 for 1h",
+		"first Monday of Smarch at 02:00 for 1h",
+		"every day at 02:00 for 1h please",
+	} {
+		if _, err := ParseTimePeriod(s, loc); err == nil {
+			t.Errorf("%q: expected error", s)
+		}
+	}
+}
+
+func TestMaintenanceBuilder(t *testing.T) {
+	start := time.Date(2021, 3, 10, 12, 0, 0, 0, time.UTC)
+	m, err := NewMaintenanceBuilder("Deploy").
+		Description("release 1.2").
+		WithoutData().
+		Hosts("10084").
+		Once(start, start.Add(time.Hour)).
+		Once(start.Add(24*time.Hour), start.Add(25*time.Hour)).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.ActiveSince != start.Unix() || m.ActiveTill != start.Add(25*time.Hour).Unix() || len(m.TimePeriods) != 2 || m.MaintenanceType != WithoutData {
+		t.Errorf("Unexpected maintenance: %#v", m)
+	}
+
+	_, err = NewMaintenanceBuilder("Patching").Groups("2").Schedule("every Sunday 02:00 for 2h").Build()
+	if err == nil {
+		t.Error("Expected error without active period")
+	}
+	m, err = NewMaintenanceBuilder("Patching").
+		Groups("2").
+		Schedule("every Sunday 02:00 for 2h").
+		Active(start, start.AddDate(1, 0, 0)).
+		Build()
+	if err != nil || m.TimePeriods[0].DayOfWeek != 64 {
+		t.Errorf("Unexpected maintenance %#v: %v", m, err)
+	}
+
+	_, err = NewMaintenanceBuilder("Broken").Hosts("1").Schedule("every Sunday").Active(start, start.Add(time.Hour)).Build()
+	if err == nil {
+		t.Error("Expected error for invalid schedule")
+	}
+	_, err = NewMaintenanceBuilder("Nobody").Once(start, start.Add(time.Hour)).Build()
+	if err == nil {
+		t.Error("Expected error without hosts")
+	}
+}
+
+func TestMaintenanceTags(t *testing.T) {
+	start := time.Date(2021, 3, 10, 12, 0, 0, 0, time.UTC)
+	m, err := NewMaintenanceBuilder("Database").
+		Hosts("10084").
+		Once(start, start.Add(time.Hour)).
+		Tag("service", TagEquals, "mysql").
+		Tag("component", TagContains, "db").
+		AnyTag().
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{`"tags":[{"tag":"service","operator":0,"value":"mysql"},{"tag":"component","operator":2,"value":"db"}]`, `"tags_evaltype":2`} {
+		if !strings.Contains(string(b), s) {
+			t.Errorf("%s not found in %s", s, b)
+		}
+	}
+
+	_, err = NewMaintenanceBuilder("Database").
+		WithoutData().
+		Hosts("10084").
+		Once(start, start.Add(time.Hour)).
+		Tag("service", TagEquals, "mysql").
+		Build()
+	if err == nil {
+		t.Error("Expected error for tags without data collection")
+	}
+	m = Maintenance{Name: "Bad", Tags: MaintenanceTags{{Tag: "service", Operator: 1}}}
+	if m.Validate() == nil {
+		t.Error("Expected error for unexpected operator")
+	}
+}
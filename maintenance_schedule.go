@@ -0,0 +1,191 @@
+package zabbix
+
+import (
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Week of month for monthly time periods with DayOfWeek, stored in Every.
+const (
+	FirstWeek  = 1
+	SecondWeek = 2
+	ThirdWeek  = 3
+	FourthWeek = 4
+	LastWeek   = 5
+)
+
+// MaintenanceWindow is a concrete interval of time when maintenance is in effect.
+type MaintenanceWindow struct {
+	Start      time.Time
+	End        time.Time // exclusive
+	TimePeriod TimePeriod
+}
+
+type MaintenanceWindows []MaintenanceWindow
+
+// Returns number of calendar day, usable for differences between dates.
+func dayNumber(t time.Time) int64 {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Unix() / 86400
+}
+
+// Returns day of week as bit of DayOfWeek: Monday is 1, Sunday is 64.
+func weekdayBit(d time.Weekday) int {
+	return 1 << uint((d+6)%7)
+}
+
+// Checks that recurring period starts on given day (in the same location as since).
+func (p *TimePeriod) startsOn(day time.Time, since time.Time) bool {
+	every := int64(p.Every)
+	if every == 0 {
+		every = 1
+	}
+
+	switch p.TimePeriodType {
+	case Daily:
+		n := dayNumber(day) - dayNumber(since)
+		return n >= 0 && n%every == 0
+
+	case Weekly:
+		if p.DayOfWeek&weekdayBit(day.Weekday()) == 0 {
+			return false
+		}
+		// weeks start on Monday and are counted from the week of since
+		monday := dayNumber(since) - int64((since.Weekday()+6)%7)
+		n := dayNumber(day) - monday
+		return n >= 0 && (n/7)%every == 0
+
+	case Monthly:
+		if p.Month&(1<<uint(day.Month()-1)) == 0 {
+			return false
+		}
+		if d, _ := strconv.Atoi(p.Day); d != 0 {
+			// months without such day are skipped
+			return day.Day() == d
+		}
+		if p.DayOfWeek&weekdayBit(day.Weekday()) == 0 {
+			return false
+		}
+		if every == LastWeek {
+			return day.AddDate(0, 0, 7).Month() != day.Month()
+		}
+		return int64((day.Day()-1)/7+1) == every
+	}
+	return false
+}
+
+// Windows returns windows of time period intersecting [from, till).
+// Recurring periods are counted from since (maintenance ActiveSince) in location loc,
+// which should be the time zone of Zabbix server.
+func (p *TimePeriod) Windows(since, from, till time.Time, loc *time.Location) (res MaintenanceWindows) {
+	period := time.Duration(p.Period) * time.Second
+	if p.TimePeriodType == OneTime {
+		start := time.Unix(p.StartDate, 0).In(loc)
+		if start.Before(till) && start.Add(period).After(from) {
+			res = append(res, MaintenanceWindow{start, start.Add(period), *p})
+		}
+		return
+	}
+
+	since = since.In(loc)
+	// windows may start before from
+	first := from.Add(-period).In(loc)
+	if first.Before(since) {
+		first = since
+	}
+	y, m, d := first.Date()
+	for day := time.Date(y, m, d, 0, 0, 0, 0, loc); day.Before(till); day = day.AddDate(0, 0, 1) {
+		if !p.startsOn(day, since) {
+			continue
+		}
+		// like mktime, seconds overflow is normalized, so StartTime is a wall clock time even on DST change days
+		start := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, int(p.StartTime), 0, loc)
+		end := start.Add(period)
+		if start.Before(till) && end.After(from) {
+			res = append(res, MaintenanceWindow{start, end, *p})
+		}
+	}
+	return
+}
+
+// Windows returns windows of all maintenance time periods intersecting [from, till),
+// limited by ActiveSince and ActiveTill (if set) and sorted by start time.
+// Location loc should be the time zone of Zabbix server.
+func (m *Maintenance) Windows(from, till time.Time, loc *time.Location) (res MaintenanceWindows) {
+	since, until := time.Unix(m.ActiveSince, 0).In(loc), time.Unix(m.ActiveTill, 0).In(loc)
+	for i := range m.TimePeriods {
+		for _, w := range m.TimePeriods[i].Windows(since, from, till, loc) {
+			if w.Start.Before(since) {
+				w.Start = since
+			}
+			if m.ActiveTill != 0 && w.End.After(until) {
+				w.End = until
+			}
+			if w.Start.Before(w.End) {
+				res = append(res, w)
+			}
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool { return res[i].Start.Before(res[j].Start) })
+	return
+}
+
+// ActiveAt checks if maintenance is in effect at time t.
+// Location loc should be the time zone of Zabbix server.
+func (m *Maintenance) ActiveAt(t time.Time, loc *time.Location) bool {
+	return len(m.Windows(t, t.Add(time.Second), loc)) > 0
+}
+
+// HasHost checks if maintenance is assigned to host directly or to one of its host groups.
+// Both IDs and objects returned by MaintenancesGet are checked.
+func (m *Maintenance) HasHost(hostId string, groupIds []string) bool {
+	for _, id := range m.HostIDs {
+		if id == hostId {
+			return true
+		}
+	}
+	for _, h := range m.Hosts {
+		if h.HostId == hostId {
+			return true
+		}
+	}
+	for _, groupId := range groupIds {
+		for _, id := range m.HostGroupIDs {
+			if id == groupId {
+				return true
+			}
+		}
+		for _, g := range m.HostGroups {
+			if g.GroupId == groupId {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// HostMaintenancesAt returns maintenances of host (directly or by host groups) in effect at time t,
+// calculated from schedules without waiting for server.
+// Location loc should be the time zone of Zabbix server.
+func (api *API) HostMaintenancesAt(hostId string, t time.Time, loc *time.Location) (res Maintenances, err error) {
+	groups, err := api.HostGroupsGet(Params{"hostids": hostId})
+	if err != nil {
+		return
+	}
+	groupIds := make([]string, len(groups))
+	for i, g := range groups {
+		groupIds[i] = g.GroupId
+	}
+
+	maintenances, err := api.MaintenancesGet(Params{})
+	if err != nil {
+		return
+	}
+	for _, m := range maintenances {
+		if m.HasHost(hostId, groupIds) && m.ActiveAt(t, loc) {
+			res = append(res, m)
+		}
+	}
+	return
+}
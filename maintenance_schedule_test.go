@@ -0,0 +1,114 @@
+package zabbix_test
+
+import (
+	"testing"
+	"time"
+
+	. "."
+)
+
+func TestMaintenanceWindows(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip(err)
+	}
+	date := func(s string) time.Time {
+		res, err := time.ParseInLocation("2006-01-02 15:04", s, loc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+
+	for name, c := range map[string]struct {
+		period   TimePeriod
+		from     string
+		till     string
+		expected []string // window starts
+	}{
+		"one time": {
+			TimePeriod{TimePeriodType: OneTime, StartDate: date("2021-03-10 12:00").Unix(), Period: 3600},
+			"2021-03-10 12:30", "2021-03-11 00:00",
+			[]string{"2021-03-10 12:00"},
+		},
+		"every 2 days at 23:00, overlapping from": {
+			TimePeriod{TimePeriodType: Daily, Every: 2, StartTime: 23 * 3600, Period: 2 * 3600},
+			"2021-03-03 00:30", "2021-03-08 00:00",
+			[]string{"2021-03-02 23:00", "2021-03-04 23:00", "2021-03-06 23:00"},
+		},
+		"daily across DST change": {
+			TimePeriod{TimePeriodType: Daily, Every: 1, StartTime: 2*3600 + 30*60, Period: 600},
+			"2021-03-27 00:00", "2021-03-30 00:00",
+			[]string{"2021-03-27 02:30", "2021-03-28 03:30", "2021-03-29 02:30"},
+		},
+		"every 2 weeks on Monday and Sunday, counted from week of ActiveSince": {
+			TimePeriod{TimePeriodType: Weekly, Every: 2, DayOfWeek: 1 | 64, StartTime: 7200, Period: 3600},
+			"2021-03-01 00:00", "2021-03-29 00:00",
+			[]string{"2021-03-08 02:00", "2021-03-14 02:00", "2021-03-22 02:00", "2021-03-28 02:00"},
+		},
+		"15th of January and February": {
+			TimePeriod{TimePeriodType: Monthly, Month: 1 | 2, Day: "15", StartTime: 0, Period: 86400},
+			"2021-01-01 00:00", "2022-01-01 00:00",
+			[]string{"2021-01-15 00:00", "2021-02-15 00:00"},
+		},
+		"31st is skipped in short months": {
+			TimePeriod{TimePeriodType: Monthly, Month: 2 | 8 | 32, Day: "31", StartTime: 0, Period: 3600},
+			"2021-01-01 00:00", "2022-01-01 00:00",
+			nil,
+		},
+		"first Monday of January and July": {
+			TimePeriod{TimePeriodType: Monthly, Month: 1 | 64, Every: FirstWeek, DayOfWeek: 1, StartTime: 22 * 3600, Period: 4 * 3600},
+			"2021-01-01 00:00", "2022-01-01 00:00",
+			[]string{"2021-01-04 22:00", "2021-07-05 22:00"},
+		},
+		"last Friday of every month in spring": {
+			TimePeriod{TimePeriodType: Monthly, Month: 4 | 8 | 16, Every: LastWeek, DayOfWeek: 16, StartTime: 0, Period: 3600},
+			"2021-01-01 00:00", "2022-01-01 00:00",
+			[]string{"2021-03-26 00:00", "2021-04-30 00:00", "2021-05-28 00:00"},
+		},
+	} {
+		m := Maintenance{
+			ActiveSince: date("2021-01-01 00:00").Unix(),
+			ActiveTill:  date("2022-01-01 00:00").Unix(),
+			TimePeriods: TimePeriods{c.period},
+		}
+		windows := m.Windows(date(c.from), date(c.till), loc)
+		if len(windows) != len(c.expected) {
+			t.Errorf("%s: expected %d windows, got %v", name, len(c.expected), windows)
+			continue
+		}
+		for i, w := range windows {
+			if !w.Start.Equal(date(c.expected[i])) || w.End.Sub(w.Start) != time.Duration(c.period.Period)*time.Second {
+				t.Errorf("%s: window %d: expected %s, got %s - %s", name, i, c.expected[i], w.Start, w.End)
+			}
+		}
+	}
+}
+
+func TestMaintenanceActiveAt(t *testing.T) {
+	since := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	m := Maintenance{
+		ActiveSince: since.Unix(),
+		ActiveTill:  since.AddDate(0, 1, 0).Unix(),
+		HostGroups:  HostGroups{{GroupId: "2"}},
+		HostIDs:     []string{"10"},
+		TimePeriods: TimePeriods{{TimePeriodType: Weekly, Every: 1, DayOfWeek: 64, StartTime: 3600, Period: 3600}},
+	}
+
+	for at, expected := range map[time.Time]bool{
+		time.Date(2021, 1, 3, 1, 0, 0, 0, time.UTC):   true,  // Sunday
+		time.Date(2021, 1, 3, 1, 59, 0, 0, time.UTC):  true,  // Sunday
+		time.Date(2021, 1, 3, 2, 0, 0, 0, time.UTC):   false, // end is exclusive
+		time.Date(2021, 1, 4, 1, 30, 0, 0, time.UTC):  false, // Monday
+		time.Date(2021, 1, 31, 1, 30, 0, 0, time.UTC): true,
+		time.Date(2021, 2, 7, 1, 30, 0, 0, time.UTC):  false, // after ActiveTill
+	} {
+		if actual := m.ActiveAt(at, time.UTC); actual != expected {
+			t.Errorf("%s: expected %v, got %v", at, expected, actual)
+		}
+	}
+
+	if !m.HasHost("10", nil) || !m.HasHost("11", []string{"1", "2"}) || m.HasHost("11", []string{"1"}) {
+		t.Error("Unexpected HasHost result")
+	}
+}
@@ -0,0 +1,238 @@
+package zabbix
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ScopedMaintenancePrefix starts names of maintenances opened by OpenScopedMaintenance.
+// DeleteStaleScopedMaintenances uses it to find maintenances left by crashed processes.
+const ScopedMaintenancePrefix = "[scoped] "
+
+// ScopedMaintenanceOptions describe maintenance for the duration of some work, like a deployment.
+type ScopedMaintenanceOptions struct {
+	Owner       string        // included in name, for example, "deploy api v1.2"
+	Hosts       []string      // technical host names
+	Groups      []string      // host group names
+	Duration    time.Duration // initial duration and extension step, 1 hour by default
+	WithoutData bool          // disable data collection
+	Tags        MaintenanceTags
+}
+
+// ScopedMaintenance is an open one-time maintenance.
+// While it is open, it is extended periodically, so if the process crashes it expires soon.
+type ScopedMaintenance struct {
+	api  *API
+	opts ScopedMaintenanceOptions
+
+	m      sync.Mutex
+	maint  Maintenance
+	err    error // first extension error
+	closed bool
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// Resolves host and host group names to IDs.
+func (api *API) scopedMaintenanceIds(opts ScopedMaintenanceOptions) (hostIds, groupIds []string, err error) {
+	hostIds, groupIds = []string{}, []string{}
+	if len(opts.Hosts) > 0 {
+		var hosts Hosts
+		if hosts, err = api.HostsGet(Params{"filter": Params{"host": opts.Hosts}}); err != nil {
+			return
+		}
+		if len(hosts) != len(opts.Hosts) {
+			return nil, nil, fmt.Errorf("Expected hosts %q, got %d.", opts.Hosts, len(hosts))
+		}
+		for _, h := range hosts {
+			hostIds = append(hostIds, h.HostId)
+		}
+	}
+	if len(opts.Groups) > 0 {
+		var groups HostGroups
+		if groups, err = api.HostGroupsGet(Params{"filter": Params{"name": opts.Groups}}); err != nil {
+			return
+		}
+		if len(groups) != len(opts.Groups) {
+			return nil, nil, fmt.Errorf("Expected host groups %q, got %d.", opts.Groups, len(groups))
+		}
+		for _, g := range groups {
+			groupIds = append(groupIds, g.GroupId)
+		}
+	}
+	if len(hostIds) == 0 && len(groupIds) == 0 {
+		err = fmt.Errorf("No hosts and host groups for scoped maintenance.")
+	}
+	return
+}
+
+// OpenScopedMaintenance creates one-time maintenance starting now for given hosts and host groups.
+// It is extended by opts.Duration every half of opts.Duration until Close is called or ctx is done;
+// in both cases it is deleted. Close should always be called to release resources.
+func (api *API) OpenScopedMaintenance(ctx context.Context, opts ScopedMaintenanceOptions) (s *ScopedMaintenance, err error) {
+	if opts.Duration <= 0 {
+		opts.Duration = time.Hour
+	}
+	hostIds, groupIds, err := api.scopedMaintenanceIds(opts)
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	till := now.Add(opts.Duration)
+	m := Maintenance{
+		Name:         fmt.Sprintf("%s%s %s", ScopedMaintenancePrefix, opts.Owner, now.UTC().Format(time.RFC3339Nano)),
+		Description:  fmt.Sprintf("Opened by %s for %s, deleted when finished or after expiration.", opts.Owner, strings.Join(append(append([]string{}, opts.Hosts...), opts.Groups...), ", ")),
+		ActiveSince:  now.Unix(),
+		ActiveTill:   till.Unix(),
+		HostIDs:      hostIds,
+		HostGroupIDs: groupIds,
+		TimePeriods:  TimePeriods{{TimePeriodType: OneTime, StartDate: now.Unix(), Period: till.Unix() - now.Unix()}},
+		Tags:         opts.Tags,
+	}
+	if opts.WithoutData {
+		m.MaintenanceType = WithoutData
+	}
+	maintenances := Maintenances{m}
+	if err = api.MaintenancesCreate(maintenances); err != nil {
+		return
+	}
+
+	s = &ScopedMaintenance{
+		api:   api,
+		opts:  opts,
+		maint: maintenances[0],
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+	go s.run(ctx)
+	return
+}
+
+// Extends maintenance periodically, deletes it when stopped.
+func (s *ScopedMaintenance) run(ctx context.Context) {
+	defer close(s.done)
+
+	t := time.NewTicker(s.opts.Duration / 2)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			if err := s.Extend(s.opts.Duration); err != nil {
+				s.m.Lock()
+				if s.err == nil {
+					s.err = err
+				}
+				s.m.Unlock()
+			}
+		case <-ctx.Done():
+			s.close()
+			return
+		case <-s.stop:
+			s.close()
+			return
+		}
+	}
+}
+
+// Deletes maintenance once.
+func (s *ScopedMaintenance) close() {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if s.closed {
+		return
+	}
+	s.closed = true
+	if err := s.api.MaintenancesDeleteByIDs([]string{s.maint.MaintenanceID}); err != nil && s.err == nil {
+		s.err = err
+	}
+}
+
+// Maintenance returns current state of maintenance.
+func (s *ScopedMaintenance) Maintenance() Maintenance {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return s.maint
+}
+
+// Extend makes maintenance last at least d from now. It is called periodically, but may be called explicitly
+// before long steps.
+func (s *ScopedMaintenance) Extend(d time.Duration) (err error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if s.closed {
+		return fmt.Errorf("Maintenance %q is closed.", s.maint.Name)
+	}
+	till := time.Now().Add(d).Unix()
+	if till <= s.maint.ActiveTill {
+		return
+	}
+
+	m := s.maint
+	m.ActiveTill = till
+	m.TimePeriods = TimePeriods{{TimePeriodType: OneTime, StartDate: m.ActiveSince, Period: till - m.ActiveSince}}
+	if err = s.api.MaintenancesUpdate(Maintenances{m}); err == nil {
+		s.maint = m
+	}
+	return
+}
+
+// Close stops extension and deletes maintenance. It returns the first error of extension or deletion.
+// It is safe to call Close more than once.
+func (s *ScopedMaintenance) Close() error {
+	s.stopOnce.Do(func() { close(s.stop) })
+	<-s.done
+
+	s.m.Lock()
+	defer s.m.Unlock()
+	return s.err
+}
+
+// WithScopedMaintenance calls f while scoped maintenance is open; maintenance is deleted when f returns,
+// panics, or ctx is done. Error of f takes precedence over maintenance errors.
+func (api *API) WithScopedMaintenance(ctx context.Context, opts ScopedMaintenanceOptions, f func(ctx context.Context) error) (err error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	s, err := api.OpenScopedMaintenance(ctx, opts)
+	if err != nil {
+		return
+	}
+	defer func() {
+		if closeErr := s.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+	return f(ctx)
+}
+
+// DeleteStaleScopedMaintenances deletes maintenances opened by OpenScopedMaintenance which expired
+// before given time, for example, because process crashed. It returns names of deleted maintenances.
+func (api *API) DeleteStaleScopedMaintenances(before time.Time) (names []string, err error) {
+	maintenances, err := api.MaintenancesGet(Params{
+		"search":      Params{"name": ScopedMaintenancePrefix},
+		"startSearch": true,
+	})
+	if err != nil {
+		return
+	}
+
+	var ids []string
+	for _, m := range maintenances {
+		if strings.HasPrefix(m.Name, ScopedMaintenancePrefix) && m.ActiveTill < before.Unix() {
+			ids = append(ids, m.MaintenanceID)
+			names = append(names, m.Name)
+		}
+	}
+	if len(ids) > 0 {
+		err = api.MaintenancesDeleteByIDs(ids)
+	}
+	return
+}
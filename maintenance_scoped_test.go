@@ -0,0 +1,160 @@
+package zabbix_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	. "."
+)
+
+// fakeMaintenanceServer implements maintenance, host and host group methods used by scoped maintenances.
+type fakeMaintenanceServer struct {
+	m            sync.Mutex
+	maintenances map[string]map[string]interface{}
+	calls        []string
+}
+
+func (f *fakeMaintenanceServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Method string          `json:"method"`
+		Params json.RawMessage `json:"params"`
+		Id     int32           `json:"id"`
+	}
+	json.NewDecoder(r.Body).Decode(&req)
+
+	f.m.Lock()
+	defer f.m.Unlock()
+	f.calls = append(f.calls, req.Method)
+
+	var result interface{}
+	switch req.Method {
+	case "APIInfo.version":
+		result = "5.0.0"
+	case "host.get":
+		result = []interface{}{map[string]interface{}{"hostid": "10084", "host": "web-1"}}
+	case "hostgroup.get":
+		result = []interface{}{}
+	case "maintenance.create", "maintenance.update":
+		var maintenances []map[string]interface{}
+		json.Unmarshal(req.Params, &maintenances)
+		m := maintenances[0]
+		if req.Method == "maintenance.create" {
+			m["maintenanceid"] = "7"
+		}
+		f.maintenances[m["maintenanceid"].(string)] = m
+		result = map[string]interface{}{"maintenanceids": []interface{}{m["maintenanceid"]}}
+	case "maintenance.delete":
+		var ids []string
+		json.Unmarshal(req.Params, &ids)
+		for _, id := range ids {
+			delete(f.maintenances, id)
+		}
+		result = map[string]interface{}{"maintenanceids": ids}
+	case "maintenance.get":
+		var res []interface{}
+		for _, m := range f.maintenances {
+			res = append(res, m)
+		}
+		result = res
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.Id, "result": result})
+}
+
+func (f *fakeMaintenanceServer) count(method string) (n int) {
+	f.m.Lock()
+	defer f.m.Unlock()
+	for _, c := range f.calls {
+		if c == method {
+			n++
+		}
+	}
+	return
+}
+
+// Returns copy of maintenances by ID.
+func (f *fakeMaintenanceServer) current() map[string]map[string]interface{} {
+	f.m.Lock()
+	defer f.m.Unlock()
+	res := make(map[string]map[string]interface{}, len(f.maintenances))
+	for id, m := range f.maintenances {
+		res[id] = m
+	}
+	return res
+}
+
+func newFakeMaintenanceServer(t *testing.T) (*fakeMaintenanceServer, *API) {
+	f := &fakeMaintenanceServer{maintenances: make(map[string]map[string]interface{})}
+	s := httptest.NewServer(f)
+	t.Cleanup(s.Close)
+	return f, NewAPI(s.URL)
+}
+
+func TestScopedMaintenance(t *testing.T) {
+	f, api := newFakeMaintenanceServer(t)
+
+	opts := ScopedMaintenanceOptions{Owner: "deploy", Hosts: []string{"web-1"}, Duration: 100 * time.Millisecond}
+	err := api.WithScopedMaintenance(context.Background(), opts, func(ctx context.Context) error {
+		maintenances := f.current()
+		if len(maintenances) != 1 {
+			t.Errorf("Expected maintenance, got %v", maintenances)
+		}
+		m := maintenances["7"]
+		if !strings.HasPrefix(m["name"].(string), ScopedMaintenancePrefix+"deploy ") || m["hostids"].([]interface{})[0] != "10084" {
+			t.Errorf("Unexpected maintenance %v", m)
+		}
+		time.Sleep(1200 * time.Millisecond)
+		return errors.New("deploy failed")
+	})
+	if err == nil || err.Error() != "deploy failed" {
+		t.Errorf("Expected error of function, got %v", err)
+	}
+	if f.count("maintenance.update") == 0 {
+		t.Error("Maintenance is not extended")
+	}
+	if m := f.current(); len(m) != 0 {
+		t.Errorf("Maintenance is not deleted: %v", m)
+	}
+
+	// context cancellation
+	ctx, cancel := context.WithCancel(context.Background())
+	s, err := api.OpenScopedMaintenance(ctx, ScopedMaintenanceOptions{Owner: "deploy", Hosts: []string{"web-1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	for i := 0; i < 100 && f.count("maintenance.delete") < 2; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if f.count("maintenance.delete") != 2 {
+		t.Error("Maintenance is not deleted on context cancellation")
+	}
+	if err = s.Close(); err != nil {
+		t.Error(err)
+	}
+	if err = s.Extend(time.Hour); err == nil {
+		t.Error("Expected error for closed maintenance")
+	}
+}
+
+func TestDeleteStaleScopedMaintenances(t *testing.T) {
+	f, api := newFakeMaintenanceServer(t)
+	now := time.Now()
+	f.maintenances["1"] = map[string]interface{}{"maintenanceid": "1", "name": ScopedMaintenancePrefix + "crashed", "active_till": now.Add(-time.Hour).Unix()}
+	f.maintenances["2"] = map[string]interface{}{"maintenanceid": "2", "name": ScopedMaintenancePrefix + "running", "active_till": now.Add(time.Hour).Unix()}
+	f.maintenances["3"] = map[string]interface{}{"maintenanceid": "3", "name": "Weekly patching", "active_till": now.Add(-time.Hour).Unix()}
+
+	names, err := api.DeleteStaleScopedMaintenances(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 1 || names[0] != ScopedMaintenancePrefix+"crashed" || len(f.current()) != 2 {
+		t.Errorf("Unexpected result %v, left %v", names, f.current())
+	}
+}
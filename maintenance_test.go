@@ -0,0 +1,52 @@
+package zabbix_test
+
+import (
+	"reflect"
+	"testing"
+
+	. "."
+)
+
+func TestMaintenancesGetTags(t *testing.T) {
+	api, requests := fakeAPI(t, map[string]string{
+		"APIInfo.version": `"5.0.0"`,
+		"maintenance.get": `[{"maintenanceid": "3", "name": "Database", "maintenance_type": "0", "tags_evaltype": "2",
+			"tags": [{"tag": "service", "operator": "0", "value": "mysql"}, {"tag": "component", "operator": "2", "value": "db"}]}]`,
+		"maintenance.update": `{"maintenanceids": ["3"]}`,
+	})
+
+	m, err := api.MaintenanceGetByID("3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := MaintenanceTags{{Tag: "service", Operator: TagEquals, Value: "mysql"}, {Tag: "component", Operator: TagContains, Value: "db"}}
+	if !reflect.DeepEqual(m.Tags, expected) || m.TagsEvalType != TagsOr {
+		t.Errorf("Unexpected tags %#v, evaluation type %d", m.Tags, m.TagsEvalType)
+	}
+	if params := (*requests)[1]["params"].(map[string]interface{}); params["selectTags"] != "extend" {
+		t.Errorf("Tags are not selected: %v", params)
+	}
+
+	m.TagsEvalType = TagsAndOr
+	if err = api.MaintenancesUpdate(Maintenances{m}); err != nil {
+		t.Fatal(err)
+	}
+	if sent := (*requests)[2]["params"].([]interface{})[0].(map[string]interface{}); sent["tags_evaltype"] != 0.0 {
+		t.Errorf("Unexpected tags evaluation type in %v", sent)
+	}
+
+	api, requests = fakeAPI(t, map[string]string{"APIInfo.version": `"3.4.0"`, "maintenance.create": `{"maintenanceids": ["4"]}`})
+	if _, err = api.MaintenancesGet(Params{}); err != nil {
+		t.Fatal(err)
+	}
+	if params := (*requests)[1]["params"].(map[string]interface{}); params["selectTags"] != nil {
+		t.Errorf("Tags are selected before Zabbix 4.0: %v", params)
+	}
+	if err = api.MaintenancesCreate(Maintenances{{Name: "Network"}}); err != nil {
+		t.Fatal(err)
+	}
+	sent := (*requests)[2]["params"].([]interface{})[0].(map[string]interface{})
+	if _, present := sent["tags_evaltype"]; present || sent["name"] != "Network" {
+		t.Errorf("Unexpected params %v", sent)
+	}
+}
@@ -0,0 +1,181 @@
+package zabbix
+
+type (
+	MediaTypeType       int
+	MessageTemplateType int
+)
+
+const (
+	MediaEmail   MediaTypeType = 0
+	MediaScript  MediaTypeType = 1
+	MediaSMS     MediaTypeType = 2
+	MediaJabber  MediaTypeType = 3 // before Zabbix 5.2
+	MediaWebhook MediaTypeType = 4 // Zabbix 5.0+
+)
+
+const (
+	MessageOperation MessageTemplateType = 0 // problem message
+	MessageRecovery  MessageTemplateType = 1
+	MessageUpdate    MessageTemplateType = 2
+)
+
+// MediaTypeParameter is a parameter of webhook media type (Zabbix 5.0+), or of script media type (Zabbix 6.0+).
+// SortOrder is a pointer, as the first script parameter has order 0 and webhook parameters have none.
+type MediaTypeParameter struct {
+	Name      string `json:"name,omitempty"`      // webhook
+	SortOrder *int   `json:"sortorder,omitempty"` // script
+	Value     string `json:"value"`
+}
+
+type MediaTypeParameters []MediaTypeParameter
+
+// MessageTemplate is a default message of media type (Zabbix 5.0+): https://www.zabbix.com/documentation/5.0/manual/api/reference/mediatype/object#message_template
+type MessageTemplate struct {
+	EventSource SourceType          `json:"eventsource"`
+	Recovery    MessageTemplateType `json:"recovery"`
+	Subject     string              `json:"subject,omitempty"`
+	Message     string              `json:"message,omitempty"`
+}
+
+type MessageTemplates []MessageTemplate
+
+// MediaType struct from https://www.zabbix.com/documentation/5.0/manual/api/reference/mediatype/object
+// Name requires Zabbix 4.4+, use Description before.
+type MediaType struct {
+	MediaTypeId     string        `json:"mediatypeid,omitempty"`
+	Name            string        `json:"name,omitempty"`
+	Type            MediaTypeType `json:"type"`
+	Description     string        `json:"description,omitempty"`
+	Status          int           `json:"status"` // 0 - enabled, 1 - disabled
+	MaxSessions     int           `json:"maxsessions,omitempty"`
+	MaxAttempts     int           `json:"maxattempts,omitempty"`
+	AttemptInterval string        `json:"attempt_interval,omitempty"`
+
+	// Email
+	SMTPServer         string `json:"smtp_server,omitempty"`
+	SMTPPort           int    `json:"smtp_port,omitempty"`
+	SMTPHelo           string `json:"smtp_helo,omitempty"`
+	SMTPEmail          string `json:"smtp_email,omitempty"`
+	SMTPSecurity       int    `json:"smtp_security,omitempty"` // 0 - none, 1 - STARTTLS, 2 - SSL/TLS
+	SMTPVerifyHost     int    `json:"smtp_verify_host,omitempty"`
+	SMTPVerifyPeer     int    `json:"smtp_verify_peer,omitempty"`
+	SMTPAuthentication int    `json:"smtp_authentication,omitempty"` // 0 - none, 1 - username and password
+	Username           string `json:"username,omitempty"`            // also Jabber
+	Password           string `json:"passwd,omitempty"`              // also Jabber
+	ContentFormat      *int   `json:"content_type,omitempty"`        // 0 - plain text, 1 - HTML (default)
+
+	// SMS
+	GSMModem string `json:"gsm_modem,omitempty"`
+
+	// Script
+	ExecPath   string `json:"exec_path,omitempty"`
+	ExecParams string `json:"exec_params,omitempty"` // before Zabbix 6.0, use Parameters since
+
+	// Webhook
+	Parameters    MediaTypeParameters `json:"parameters,omitempty"`
+	Script        string              `json:"script,omitempty"`
+	Timeout       string              `json:"timeout,omitempty"`
+	ProcessTags   int                 `json:"process_tags,omitempty"`
+	ShowEventMenu int                 `json:"show_event_menu,omitempty"`
+	EventMenuURL  string              `json:"event_menu_url,omitempty"`
+	EventMenuName string              `json:"event_menu_name,omitempty"`
+
+	MessageTemplates MessageTemplates `json:"message_templates,omitempty"` // Zabbix 5.0+
+}
+
+type MediaTypes []MediaType
+
+// MediaTypesGet is a wrapper for mediatype.get: https://www.zabbix.com/documentation/5.0/manual/api/reference/mediatype/get
+// Use "selectMessageTemplates" to get message templates (Zabbix 5.0+).
+func (api *API) MediaTypesGet(params Params) (res MediaTypes, err error) {
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	response, err := api.CallWithError("mediatype.get", params)
+	if err != nil {
+		return
+	}
+
+	err = decode(response.Result.([]interface{}), &res)
+	return
+}
+
+// MediaTypeGetById gets media type by Id only if there is exactly 1 matching media type.
+func (api *API) MediaTypeGetById(id string) (res *MediaType, err error) {
+	mediaTypes, err := api.MediaTypesGet(Params{"mediatypeids": id})
+	if err != nil {
+		return
+	}
+
+	if len(mediaTypes) == 1 {
+		res = &mediaTypes[0]
+	} else {
+		e := ExpectedOneResult(len(mediaTypes))
+		err = &e
+	}
+	return
+}
+
+// MediaTypesCreate is a wrapper for mediatype.create: https://www.zabbix.com/documentation/5.0/manual/api/reference/mediatype/create
+func (api *API) MediaTypesCreate(mediaTypes MediaTypes) (err error) {
+	response, err := api.CallWithError("mediatype.create", mediaTypes)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	mediatypeids := result["mediatypeids"].([]interface{})
+	for i, id := range mediatypeids {
+		mediaTypes[i].MediaTypeId = id.(string)
+	}
+	return
+}
+
+// MediaTypesUpdate is a wrapper for mediatype.update: https://www.zabbix.com/documentation/5.0/manual/api/reference/mediatype/update
+// Type and Status are always sent, so media types should be updated as full objects, like ones returned by MediaTypesGet.
+// Non-empty Parameters and MessageTemplates replace existing ones.
+func (api *API) MediaTypesUpdate(mediaTypes MediaTypes) (err error) {
+	response, err := api.CallWithError("mediatype.update", mediaTypes)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	mediatypeids := result["mediatypeids"].([]interface{})
+	if len(mediaTypes) != len(mediatypeids) {
+		err = &ExpectedMore{len(mediaTypes), len(mediatypeids)}
+	}
+	return
+}
+
+// MediaTypesDelete is a wrapper for mediatype.delete: https://www.zabbix.com/documentation/5.0/manual/api/reference/mediatype/delete
+// Cleans MediaTypeId in all media types elements if call succeed.
+func (api *API) MediaTypesDelete(mediaTypes MediaTypes) (err error) {
+	ids := make([]string, len(mediaTypes))
+	for i, mediaType := range mediaTypes {
+		ids[i] = mediaType.MediaTypeId
+	}
+
+	err = api.MediaTypesDeleteByIds(ids)
+	if err == nil {
+		for i := range mediaTypes {
+			mediaTypes[i].MediaTypeId = ""
+		}
+	}
+	return
+}
+
+// MediaTypesDeleteByIds is a wrapper for mediatype.delete: https://www.zabbix.com/documentation/5.0/manual/api/reference/mediatype/delete
+func (api *API) MediaTypesDeleteByIds(ids []string) (err error) {
+	response, err := api.CallWithError("mediatype.delete", ids)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	mediatypeids := result["mediatypeids"].([]interface{})
+	if len(ids) != len(mediatypeids) {
+		err = &ExpectedMore{len(ids), len(mediatypeids)}
+	}
+	return
+}
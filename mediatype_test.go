@@ -0,0 +1,99 @@
+package zabbix_test
+
+import (
+	"testing"
+
+	. "."
+)
+
+func TestMediaTypes(t *testing.T) {
+	api, requests := fakeAPI(t, map[string]string{
+		"mediatype.get": `[{"mediatypeid": "1", "name": "Email", "type": "0", "status": "0", "smtp_server": "mail.example.com",
+			"smtp_port": "25", "message_templates": [{"eventsource": "0", "recovery": "1", "subject": "Resolved: {EVENT.NAME}", "message": "ok"}]}]`,
+		"mediatype.create": `{"mediatypeids": ["2"]}`,
+		"mediatype.update": `{"mediatypeids": ["1"]}`,
+		"mediatype.delete": `{"mediatypeids": ["2"]}`,
+		"alert.get":        `[{"alertid": "7", "eventid": "42", "mediatypeid": "1", "sendto": "ops@example.com", "status": "2", "retries": "3", "error": "Connection refused", "alerttype": "0"}]`,
+	})
+
+	m, err := api.MediaTypeGetById("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Type != MediaEmail || m.SMTPPort != 25 || m.MessageTemplates[0].Recovery != MessageRecovery {
+		t.Errorf("Unexpected media type %#v", m)
+	}
+
+	plain := 0
+	m.ContentFormat = &plain
+	if err = api.MediaTypesUpdate(MediaTypes{*m}); err != nil {
+		t.Fatal(err)
+	}
+	if params := (*requests)[1]["params"].([]interface{})[0].(map[string]interface{}); params["content_type"] != 0.0 || params["type"] != 0.0 {
+		t.Errorf("Unexpected params %v", params)
+	}
+
+	mediaTypes := MediaTypes{{
+		Name:             "SMS",
+		Type:             MediaSMS,
+		GSMModem:         "/dev/ttyS0",
+		MessageTemplates: MessageTemplates{{EventSource: SourceTrigger, Recovery: MessageOperation, Message: "{EVENT.NAME}"}},
+	}}
+	if err = api.MediaTypesCreate(mediaTypes); err != nil || mediaTypes[0].MediaTypeId != "2" {
+		t.Fatalf("Unexpected media type %#v: %v", mediaTypes[0], err)
+	}
+	params := (*requests)[2]["params"].([]interface{})[0].(map[string]interface{})
+	if params["type"] != 2.0 || params["gsm_modem"] != "/dev/ttyS0" || params["smtp_server"] != nil {
+		t.Errorf("Unexpected params %v", params)
+	}
+
+	first, second := 0, 1
+	script := MediaTypes{{
+		Name:       "Pager",
+		Type:       MediaScript,
+		ExecPath:   "pager.sh",
+		Parameters: MediaTypeParameters{{SortOrder: &first, Value: "{ALERT.SENDTO}"}, {SortOrder: &second, Value: "{ALERT.MESSAGE}"}},
+	}}
+	if err = api.MediaTypesCreate(script); err != nil {
+		t.Fatal(err)
+	}
+	params = (*requests)[3]["params"].([]interface{})[0].(map[string]interface{})
+	if p := params["parameters"].([]interface{})[0].(map[string]interface{}); p["sortorder"] != 0.0 || p["name"] != nil {
+		t.Errorf("Unexpected params %v", params)
+	}
+
+	if err = api.MediaTypesDelete(mediaTypes); err != nil || mediaTypes[0].MediaTypeId != "" {
+		t.Errorf("Unexpected media type %#v: %v", mediaTypes[0], err)
+	}
+
+	alerts, err := api.AlertsGet(Params{"eventids": "42"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(alerts) != 1 || alerts[0].Status != AlertFailed || alerts[0].Error != "Connection refused" || alerts[0].Retries != 3 {
+		t.Errorf("Unexpected alerts %#v", alerts)
+	}
+}
+
+func TestUserMediasUpdate(t *testing.T) {
+	for version, name := range map[string]string{"4.0.0": "user_medias", "5.2.0": "medias"} {
+		api, requests := fakeAPI(t, map[string]string{
+			"APIInfo.version": `"` + version + `"`,
+			"user.update":     `{"userids": ["3"]}`,
+		})
+		medias := Medias{{MediaTypeId: "1", SendTo: []string{"ops@example.com"}, Severity: SeverityMask(High, Disaster), Period: "1-5,09:00-18:00"}}
+		if err := api.UserMediasUpdate("3", medias); err != nil {
+			t.Fatal(err)
+		}
+		params := (*requests)[1]["params"].(map[string]interface{})
+		media := params[name].([]interface{})[0].(map[string]interface{})
+		if params["userid"] != "3" || media["severity"] != 48.0 || media["sendto"].([]interface{})[0] != "ops@example.com" {
+			t.Errorf("%s: unexpected params %v", version, params)
+		}
+	}
+
+	api, _ := fakeAPI(t, map[string]string{"APIInfo.version": `"5.2.0"`})
+	if err := api.UserMediasUpdate("3", Medias{{MediaTypeId: "1", Severity: 64}}); err == nil {
+		t.Error("Expected error for severity mask")
+	}
+}
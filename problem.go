@@ -0,0 +1,109 @@
+package zabbix
+
+import "sort"
+
+type ProblemTag struct {
+	Tag   string `json:"tag"`
+	Value string `json:"value"`
+}
+
+type ProblemTags []ProblemTag
+
+// ProblemSuppression describes maintenance suppressing problem.
+type ProblemSuppression struct {
+	MaintenanceId string `json:"maintenanceid"`
+	SuppressUntil int64  `json:"suppress_until"` // 0 for indefinite suppression
+}
+
+// Problem struct from https://www.zabbix.com/documentation/4.0/manual/api/reference/problem/object
+type Problem struct {
+	EventId         string               `json:"eventid"`
+	Source          SourceType           `json:"source"`
+	Object          ObjectType           `json:"object"`
+	ObjectId        string               `json:"objectid"`
+	Clock           int64                `json:"clock"`
+	Ns              int64                `json:"ns"`
+	REventId        string               `json:"r_eventid"` // recovery event ID, "0" if problem is not resolved
+	RClock          int64                `json:"r_clock"`
+	RNs             int64                `json:"r_ns"`
+	CorrelationId   string               `json:"correlationid"`
+	UserId          string               `json:"userid"`
+	Name            string               `json:"name"`
+	Acknowledged    int                  `json:"acknowledged"`
+	Severity        PriorityType         `json:"severity"`
+	Suppressed      int                  `json:"suppressed"`
+	OpData          string               `json:"opdata,omitempty"` // Zabbix 5.0+
+	Tags            ProblemTags          `json:"tags,omitempty"`
+	Acknowledges    Acknowledges         `json:"acknowledges,omitempty"`
+	SuppressionData []ProblemSuppression `json:"suppression_data,omitempty"`
+}
+
+type Problems []Problem
+
+// Resolved checks if problem has recovery event.
+func (p *Problem) Resolved() bool {
+	return p.REventId != "" && p.REventId != "0"
+}
+
+// ProblemsGet is a wrapper for problem.get: https://www.zabbix.com/documentation/4.0/manual/api/reference/problem/get
+// Tags are selected by default. Only unresolved and recently resolved problems are returned by server.
+func (api *API) ProblemsGet(params Params) (res Problems, err error) {
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	if _, present := params["selectTags"]; !present {
+		params["selectTags"] = "extend"
+	}
+	response, err := api.CallWithError("problem.get", params)
+	if err != nil {
+		return
+	}
+
+	err = decode(response.Result.([]interface{}), &res)
+	return
+}
+
+// ActiveProblem is a trigger problem with its trigger and hosts.
+type ActiveProblem struct {
+	Problem
+	Trigger Trigger // empty if trigger is not accessible
+	Hosts   Hosts
+}
+
+type ActiveProblems []ActiveProblem
+
+// ActiveProblemsGet returns trigger problems matching params (see ProblemsGet) joined with triggers and hosts,
+// sorted by severity from disaster to not classified, then from newest to oldest.
+func (api *API) ActiveProblemsGet(params Params) (res ActiveProblems, err error) {
+	params["source"], params["object"] = SourceTrigger, ObjectTrigger
+	problems, err := api.ProblemsGet(params)
+	if err != nil || len(problems) == 0 {
+		return
+	}
+
+	ids := make([]string, 0, len(problems))
+	for _, p := range problems {
+		ids = append(ids, p.ObjectId)
+	}
+	triggers, err := api.TriggersGet(Params{"triggerids": ids, "selectHosts": "extend", "expandDescription": true})
+	if err != nil {
+		return
+	}
+	byId := make(map[string]Trigger, len(triggers))
+	for _, t := range triggers {
+		byId[t.TriggerId] = t
+	}
+
+	res = make(ActiveProblems, len(problems))
+	for i, p := range problems {
+		t := byId[p.ObjectId]
+		res[i] = ActiveProblem{Problem: p, Trigger: t, Hosts: t.Hosts}
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		if res[i].Severity != res[j].Severity {
+			return res[i].Severity > res[j].Severity
+		}
+		return res[i].Clock > res[j].Clock
+	})
+	return
+}
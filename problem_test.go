@@ -0,0 +1,56 @@
+package zabbix_test
+
+import (
+	"testing"
+
+	. "."
+)
+
+func TestActiveProblemsGet(t *testing.T) {
+	api, requests := fakeAPI(t, map[string]string{
+		"problem.get": `[
+			{"eventid": "1", "source": "0", "object": "0", "objectid": "10", "clock": "100", "r_eventid": "0", "name": "Disk is full",
+			 "acknowledged": "0", "severity": "3", "suppressed": "0", "tags": [{"tag": "service", "value": "db"}]},
+			{"eventid": "2", "source": "0", "object": "0", "objectid": "11", "clock": "200", "r_eventid": "0", "name": "Host is down",
+			 "acknowledged": "1", "severity": "5", "suppressed": "0", "tags": []},
+			{"eventid": "3", "source": "0", "object": "0", "objectid": "12", "clock": "300", "r_eventid": "5", "name": "High load",
+			 "acknowledged": "0", "severity": "3", "suppressed": "1", "tags": [],
+			 "suppression_data": [{"maintenanceid": "7", "suppress_until": "400"}]}
+		]`,
+		"trigger.get": `[
+			{"triggerid": "10", "description": "Disk is full", "priority": "3", "hosts": [{"hostid": "100", "host": "db-1"}]},
+			{"triggerid": "11", "description": "Host is down", "priority": "5", "hosts": [{"hostid": "101", "host": "web-1"}]}
+		]`,
+	})
+
+	problems, err := api.ActiveProblemsGet(Params{"recent": true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(problems) != 3 {
+		t.Fatalf("Expected 3 problems, got %#v", problems)
+	}
+
+	var ids []string
+	for _, p := range problems {
+		ids = append(ids, p.EventId)
+	}
+	if ids[0] != "2" || ids[1] != "3" || ids[2] != "1" {
+		t.Errorf("Unexpected order %v", ids)
+	}
+
+	p := problems[2]
+	if p.Severity != Average || p.Clock != 100 || p.Resolved() || p.Tags[0].Value != "db" ||
+		p.Trigger.TriggerId != "10" || p.Hosts[0].Host != "db-1" {
+		t.Errorf("Unexpected problem %#v", p)
+	}
+	p = problems[1]
+	if !p.Resolved() || p.Suppressed != 1 || p.SuppressionData[0].SuppressUntil != 400 || p.Trigger.TriggerId != "" {
+		t.Errorf("Unexpected problem %#v", p)
+	}
+
+	params := (*requests)[0]["params"].(map[string]interface{})
+	if params["source"] != 0.0 || params["object"] != 0.0 || params["selectTags"] != "extend" {
+		t.Errorf("Unexpected params %v", params)
+	}
+}
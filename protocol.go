@@ -0,0 +1,120 @@
+package zabbix
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+// Zabbix protocol header flags: https://www.zabbix.com/documentation/4.0/manual/appendix/protocols/header_datalen
+const (
+	ProtocolZabbix     = 0x01
+	ProtocolCompressed = 0x02
+	ProtocolLarge      = 0x04
+)
+
+// MaxPacketSize is the largest packet ReadPacket accepts, same as Zabbix server default.
+var MaxPacketSize uint64 = 1 << 30
+
+var protocolSignature = []byte("ZBXD")
+
+type ProtocolError string
+
+func (e ProtocolError) Error() string {
+	return fmt.Sprintf("Zabbix protocol error: %s.", string(e))
+}
+
+// WritePacket writes data to w with Zabbix protocol header, compressing it with zlib if asked.
+func WritePacket(w io.Writer, data []byte, compress bool) (err error) {
+	flags := byte(ProtocolZabbix)
+	reserved := uint64(0)
+	if compress {
+		var buf bytes.Buffer
+		z := zlib.NewWriter(&buf)
+		if _, err = z.Write(data); err != nil {
+			return
+		}
+		if err = z.Close(); err != nil {
+			return
+		}
+		flags |= ProtocolCompressed
+		reserved = uint64(len(data))
+		data = buf.Bytes()
+	}
+
+	header := make([]byte, 5, 21)
+	copy(header, protocolSignature)
+	if uint64(len(data)) > 0xffffffff || reserved > 0xffffffff {
+		flags |= ProtocolLarge
+		header = header[:21]
+		binary.LittleEndian.PutUint64(header[5:], uint64(len(data)))
+		binary.LittleEndian.PutUint64(header[13:], reserved)
+	} else {
+		header = header[:13]
+		binary.LittleEndian.PutUint32(header[5:], uint32(len(data)))
+		binary.LittleEndian.PutUint32(header[9:], uint32(reserved))
+	}
+	header[4] = flags
+
+	_, err = w.Write(append(header, data...))
+	return
+}
+
+// ReadPacket reads one packet with Zabbix protocol header from r and returns decompressed data.
+func ReadPacket(r io.Reader) (data []byte, err error) {
+	header := make([]byte, 5)
+	if _, err = io.ReadFull(r, header); err != nil {
+		return
+	}
+	if !bytes.Equal(header[:4], protocolSignature) {
+		err = ProtocolError(fmt.Sprintf("bad signature %q", header[:4]))
+		return
+	}
+
+	flags := header[4]
+	if flags&ProtocolZabbix == 0 {
+		err = ProtocolError(fmt.Sprintf("bad flags 0x%02x", flags))
+		return
+	}
+
+	var size, reserved uint64
+	if flags&ProtocolLarge != 0 {
+		b := make([]byte, 16)
+		if _, err = io.ReadFull(r, b); err != nil {
+			return
+		}
+		size, reserved = binary.LittleEndian.Uint64(b), binary.LittleEndian.Uint64(b[8:])
+	} else {
+		b := make([]byte, 8)
+		if _, err = io.ReadFull(r, b); err != nil {
+			return
+		}
+		size, reserved = uint64(binary.LittleEndian.Uint32(b)), uint64(binary.LittleEndian.Uint32(b[4:]))
+	}
+	if size > MaxPacketSize || reserved > MaxPacketSize {
+		err = ProtocolError(fmt.Sprintf("packet size %d exceeds %d", size, MaxPacketSize))
+		return
+	}
+
+	data = make([]byte, size)
+	if _, err = io.ReadFull(r, data); err != nil {
+		return
+	}
+
+	if flags&ProtocolCompressed != 0 {
+		var z io.ReadCloser
+		z, err = zlib.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return
+		}
+		defer z.Close()
+		data, err = ioutil.ReadAll(io.LimitReader(z, int64(reserved)))
+		if err == nil && uint64(len(data)) != reserved {
+			err = ProtocolError(fmt.Sprintf("expected %d uncompressed bytes, got %d", reserved, len(data)))
+		}
+	}
+	return
+}
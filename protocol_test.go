@@ -0,0 +1,34 @@
+package zabbix_test
+
+import (
+	"bytes"
+	"testing"
+
+	. "."
+)
+
+func TestPacket(t *testing.T) {
+	for _, compress := range []bool{false, true} {
+		var buf bytes.Buffer
+		data := bytes.Repeat([]byte(`{"request":"sender data"}`), 100)
+		if err := WritePacket(&buf, data, compress); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.HasPrefix(buf.Bytes(), []byte("ZBXD")) {
+			t.Errorf("Bad header: %q", buf.Bytes()[:13])
+		}
+
+		res, err := ReadPacket(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(data, res) {
+			t.Errorf("Packets are not equal (compress %v):\n%s\n%s", compress, data, res)
+		}
+	}
+
+	_, err := ReadPacket(bytes.NewReader([]byte("agent.ping\n")))
+	if _, ok := err.(ProtocolError); !ok {
+		t.Errorf("Expected ProtocolError, got %#v", err)
+	}
+}
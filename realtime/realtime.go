@@ -0,0 +1,215 @@
+// Package realtime reads files of Zabbix real-time export (Zabbix 4.0+):
+// https://www.zabbix.com/documentation/4.0/manual/appendix/install/real_time_export
+//
+// Zabbix server writes events, history and trends as newline-delimited JSON to files
+// like problems-history-syncer-1.ndjson, history-history-syncer-1.ndjson and trends-history-syncer-1.ndjson,
+// renaming a file to *.old when it reaches ExportFileSize. Tail follows such files across rotations,
+// and records are decoded into types of zabbix package.
+package realtime
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/seuf/zabbix"
+)
+
+// Event is an exported trigger event: problem or recovery.
+type Event struct {
+	zabbix.Event
+	PEventId string             // problem event ID for recovery event
+	Groups   []string           // host group names
+	Tags     zabbix.ProblemTags // problem event tags
+}
+
+// History is an exported item value.
+type History struct {
+	zabbix.History
+	Host         zabbix.Host // only Host and Name are set
+	Groups       []string
+	Applications []string           // before Zabbix 5.4
+	Tags         zabbix.ProblemTags // item tags, Zabbix 5.4+
+	Name         string             // item name
+	Type         zabbix.ValueType   // Zabbix 5.0+
+}
+
+// Trend is an exported hourly trend of numeric item.
+type Trend struct {
+	zabbix.Trend
+	Host         zabbix.Host // only Host and Name are set
+	Groups       []string
+	Applications []string           // before Zabbix 5.4
+	Tags         zabbix.ProblemTags // item tags, Zabbix 5.4+
+	Name         string             // item name
+	Type         zabbix.ValueType   // Zabbix 5.0+
+}
+
+type rawEvent struct {
+	Clock    int64               `json:"clock"`
+	Ns       int64               `json:"ns"`
+	Value    zabbix.ValueType    `json:"value"`
+	EventId  json.Number         `json:"eventid"`
+	PEventId json.Number         `json:"p_eventid"`
+	Name     string              `json:"name"`
+	Severity zabbix.PriorityType `json:"severity"`
+	Hosts    zabbix.Hosts        `json:"hosts"`
+	Groups   []string            `json:"groups"`
+	Tags     zabbix.ProblemTags  `json:"tags"`
+}
+
+type rawItem struct {
+	Host         zabbix.Host        `json:"host"`
+	Groups       []string           `json:"groups"`
+	Applications []string           `json:"applications"`
+	ItemTags     zabbix.ProblemTags `json:"item_tags"`
+	ItemId       json.Number        `json:"itemid"`
+	Name         string             `json:"name"`
+	Clock        uint               `json:"clock"`
+	Type         zabbix.ValueType   `json:"type"`
+
+	// history
+	Ns        int             `json:"ns"`
+	Value     json.RawMessage `json:"value"`
+	Timestamp uint            `json:"timestamp"`
+	Source    string          `json:"source"`
+	Severity  int             `json:"severity"`
+	EventId   int             `json:"eventid"`
+
+	// trends
+	Count int     `json:"count"`
+	Min   float64 `json:"min"`
+	Avg   float64 `json:"avg"`
+	Max   float64 `json:"max"`
+}
+
+// DecodeEvent decodes line of events file.
+func DecodeEvent(line []byte) (res Event, err error) {
+	var r rawEvent
+	if err = json.Unmarshal(line, &r); err != nil {
+		return
+	}
+	if r.EventId == "" {
+		err = fmt.Errorf("No eventid in %s.", line)
+		return
+	}
+	res = Event{
+		Event: zabbix.Event{
+			Clock:    r.Clock,
+			Ns:       r.Ns,
+			EventId:  r.EventId.String(),
+			Source:   zabbix.SourceTrigger,
+			Object:   zabbix.ObjectTrigger,
+			Value:    r.Value,
+			Hosts:    r.Hosts,
+			Name:     r.Name,
+			Severity: r.Severity,
+		},
+		PEventId: r.PEventId.String(),
+		Groups:   r.Groups,
+		Tags:     r.Tags,
+	}
+	return
+}
+
+// DecodeHistory decodes line of history file. Value is kept as in History.Value: numbers are formatted as in JSON.
+func DecodeHistory(line []byte) (res History, err error) {
+	var r rawItem
+	if err = json.Unmarshal(line, &r); err != nil {
+		return
+	}
+	if r.ItemId == "" || len(r.Value) == 0 {
+		err = fmt.Errorf("No itemid or value in %s.", line)
+		return
+	}
+	value := string(r.Value)
+	if r.Value[0] == '"' {
+		if err = json.Unmarshal(r.Value, &value); err != nil {
+			return
+		}
+	}
+	res = History{
+		History: zabbix.History{
+			Clock:      r.Clock,
+			ItemId:     r.ItemId.String(),
+			Ns:         r.Ns,
+			Value:      value,
+			LogEventId: r.EventId,
+			Severity:   r.Severity,
+			Source:     r.Source,
+			Timestamp:  r.Timestamp,
+		},
+		Host:         r.Host,
+		Groups:       r.Groups,
+		Applications: r.Applications,
+		Tags:         r.ItemTags,
+		Name:         r.Name,
+		Type:         r.Type,
+	}
+	return
+}
+
+// DecodeTrend decodes line of trends file.
+func DecodeTrend(line []byte) (res Trend, err error) {
+	var r rawItem
+	if err = json.Unmarshal(line, &r); err != nil {
+		return
+	}
+	if r.ItemId == "" || r.Count == 0 {
+		err = fmt.Errorf("No itemid or count in %s.", line)
+		return
+	}
+	res = Trend{
+		Trend: zabbix.Trend{
+			ItemId:   r.ItemId.String(),
+			Clock:    r.Clock,
+			Num:      r.Count,
+			ValueMin: r.Min,
+			ValueAvg: r.Avg,
+			ValueMax: r.Max,
+		},
+		Host:         r.Host,
+		Groups:       r.Groups,
+		Applications: r.Applications,
+		Tags:         r.ItemTags,
+		Name:         r.Name,
+		Type:         r.Type,
+	}
+	return
+}
+
+// Record is a decoded line of any export file, see Stream.
+type Record struct {
+	Offset  int64 // after the line, in the current file
+	Event   *Event
+	History *History
+	Trend   *Trend
+	Err     error // error which stopped Stream, set only in the last record
+}
+
+// Decodes line detecting its type by fields.
+func decodeRecord(line []byte) (r Record, err error) {
+	var fields map[string]json.RawMessage
+	if err = json.Unmarshal(line, &fields); err != nil {
+		return
+	}
+	switch {
+	case fields["eventid"] != nil && fields["itemid"] == nil:
+		var e Event
+		e, err = DecodeEvent(line)
+		r.Event = &e
+	case fields["count"] != nil:
+		var t Trend
+		t, err = DecodeTrend(line)
+		r.Trend = &t
+	default:
+		var h History
+		h, err = DecodeHistory(line)
+		r.History = &h
+	}
+	return
+}
+
+// Wraps decoding error with position.
+func lineError(err error, offset int64) error {
+	return fmt.Errorf("Failed to decode line ending at %d: %s", offset, err)
+}
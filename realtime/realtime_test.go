@@ -0,0 +1,143 @@
+package realtime_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "."
+	"github.com/seuf/zabbix"
+)
+
+func TestTailEvents(t *testing.T) {
+	var events []Event
+	err := TailEvents(context.Background(), "testdata/problems.ndjson", Options{}, func(e Event) error {
+		events = append(events, e)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 events, got %#v", events)
+	}
+
+	e := events[0]
+	if e.EventId != "42" || e.Value != zabbix.TriggerProblem || e.Severity != zabbix.Average || e.Ns != 123456789 ||
+		e.Hosts[1].Name != "Zabbix Server visible" || e.Groups[3] != "Zabbix servers" || e.Tags[1].Value != "Riga" {
+		t.Errorf("Unexpected problem %#v", e)
+	}
+	e = events[1]
+	if e.EventId != "43" || e.PEventId != "42" || e.Value != zabbix.TriggerOk {
+		t.Errorf("Unexpected recovery %#v", e)
+	}
+}
+
+func TestTailHistory(t *testing.T) {
+	var values []History
+	err := TailHistory(context.Background(), "testdata/history.ndjson", Options{}, func(h History) error {
+		values = append(values, h)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(values) != 3 {
+		t.Fatalf("Expected 3 values, got %#v", values)
+	}
+
+	if f, err := values[0].Float(); err != nil || f != 1 || values[0].ItemId != "3" || values[0].Host.Host != "Host B" || values[0].Applications[0] != "Zabbix Agent" {
+		t.Errorf("Unexpected value %#v: %v", values[0], err)
+	}
+	if values[1].Value != `up "ok"` || values[1].Type != zabbix.Character {
+		t.Errorf("Unexpected value %#v", values[1])
+	}
+	if values[2].Timestamp != 1519304280 || values[2].Type != zabbix.Log || values[2].Tags[0].Tag != "component" {
+		t.Errorf("Unexpected value %#v", values[2])
+	}
+}
+
+func TestTailTrends(t *testing.T) {
+	var trends []Trend
+	err := TailTrends(context.Background(), "testdata/trends.ndjson", Options{}, func(tr Trend) error {
+		trends = append(trends, tr)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trends) != 2 || trends[1].Num != 60 || trends[1].ValueAvg != 0.5 || trends[1].ValueMax != 1.5 || trends[1].Name != "Load" {
+		t.Errorf("Unexpected trends %#v", trends)
+	}
+
+	err = TailTrends(context.Background(), "testdata/history.ndjson", Options{}, func(Trend) error { return nil })
+	if err == nil {
+		t.Error("Expected error for history file")
+	}
+}
+
+func TestStreamRotation(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "problems-history-syncer-1.ndjson")
+	data, err := ioutil.ReadFile("testdata/problems.ndjson")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := [][]byte{data[:len(data)/2], data[len(data)/2:]} // first write ends in the middle of line
+	if err = ioutil.WriteFile(path, lines[0], 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := Stream(ctx, path, Options{Follow: true, Interval: 10 * time.Millisecond})
+
+	write := func(b []byte) {
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write(b)
+		f.Close()
+	}
+	time.Sleep(50 * time.Millisecond)
+	write(lines[1])
+	if err = os.Rename(path, path+".old"); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	write(data)
+
+	var ids []string
+	var offset int64
+	for r := range ch {
+		if r.Err != nil {
+			t.Fatal(r.Err)
+		}
+		ids = append(ids, r.Event.EventId)
+		offset = r.Offset
+		if len(ids) == 4 {
+			cancel()
+		}
+	}
+	if len(ids) != 4 || ids[0] != "42" || ids[1] != "43" || ids[2] != "42" || ids[3] != "43" {
+		t.Errorf("Unexpected events %v", ids)
+	}
+	if offset != int64(len(data)) {
+		t.Errorf("Expected offset %d in new file, got %d", len(data), offset)
+	}
+
+	// resume from offset of the first line
+	var n int
+	err = Tail(context.Background(), path, Options{Offset: int64(bytes.IndexByte(data, '\n') + 1)}, func(line []byte, offset int64) error {
+		n++
+		return nil
+	})
+	if err != nil || n != 1 {
+		t.Errorf("Expected 1 line after offset, got %d: %v", n, err)
+	}
+}
@@ -0,0 +1,165 @@
+package realtime
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"time"
+)
+
+// Options of Tail.
+type Options struct {
+	Follow   bool          // wait for new lines and follow rotation, otherwise stop at the end of file
+	Interval time.Duration // polling interval when following, 1 second by default
+	Offset   int64         // start position in file, for example, Record.Offset saved before restart
+}
+
+// Checks if file at path is not the opened file anymore, or was truncated below pos.
+// Missing file is not rotated yet: Zabbix renames it first and creates a new one on the next write.
+func rotated(f *os.File, path string, pos int64) (bool, error) {
+	fi, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	cur, err := f.Stat()
+	if err != nil {
+		return false, err
+	}
+	return !os.SameFile(cur, fi) || fi.Size() < pos, nil
+}
+
+// Tail calls f for every non-empty line of file with offset after the line.
+// Incomplete last line is not passed to f until it is terminated by newline.
+// With opts.Follow it waits for new lines until ctx is done, and switches to a new file
+// after the current one is rotated and read to the end. It stops on the first error of f.
+func Tail(ctx context.Context, path string, opts Options, f func(line []byte, offset int64) error) (err error) {
+	if opts.Interval <= 0 {
+		opts.Interval = time.Second
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer func() { file.Close() }()
+
+	offset := opts.Offset
+	if _, err = file.Seek(offset, io.SeekStart); err != nil {
+		return
+	}
+	r := bufio.NewReader(file)
+	var partial []byte
+	draining := false // rotation is detected, reading the rest of old file
+	for {
+		b, err := r.ReadBytes('\n')
+		partial = append(partial, b...)
+		if err == nil {
+			offset += int64(len(partial))
+			line := bytes.TrimSpace(partial)
+			partial = nil
+			if len(line) == 0 {
+				continue
+			}
+			if err = f(line, offset); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != io.EOF {
+			return err
+		}
+		if !opts.Follow {
+			return nil
+		}
+
+		if draining {
+			next, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			file.Close()
+			file, offset, partial, draining = next, 0, nil, false
+			r.Reset(file)
+			continue
+		}
+		if draining, err = rotated(file, path, offset+int64(len(partial))); err != nil {
+			return err
+		}
+		if draining {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(opts.Interval):
+		}
+	}
+}
+
+// TailEvents calls f for every event in events file, see Tail.
+func TailEvents(ctx context.Context, path string, opts Options, f func(Event) error) error {
+	return Tail(ctx, path, opts, func(line []byte, offset int64) error {
+		e, err := DecodeEvent(line)
+		if err != nil {
+			return lineError(err, offset)
+		}
+		return f(e)
+	})
+}
+
+// TailHistory calls f for every value in history file, see Tail.
+func TailHistory(ctx context.Context, path string, opts Options, f func(History) error) error {
+	return Tail(ctx, path, opts, func(line []byte, offset int64) error {
+		h, err := DecodeHistory(line)
+		if err != nil {
+			return lineError(err, offset)
+		}
+		return f(h)
+	})
+}
+
+// TailTrends calls f for every trend in trends file, see Tail.
+func TailTrends(ctx context.Context, path string, opts Options, f func(Trend) error) error {
+	return Tail(ctx, path, opts, func(line []byte, offset int64) error {
+		t, err := DecodeTrend(line)
+		if err != nil {
+			return lineError(err, offset)
+		}
+		return f(t)
+	})
+}
+
+// Stream tails file of any type in background and sends decoded records to returned channel.
+// Channel is closed when Tail returns; if it failed before ctx is done, the last record has Err set.
+// Records are not sent after ctx is done.
+func Stream(ctx context.Context, path string, opts Options) <-chan Record {
+	ch := make(chan Record)
+	go func() {
+		defer close(ch)
+		err := Tail(ctx, path, opts, func(line []byte, offset int64) error {
+			r, err := decodeRecord(line)
+			if err != nil {
+				return lineError(err, offset)
+			}
+			r.Offset = offset
+			select {
+			case ch <- r:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		if err != nil && ctx.Err() == nil {
+			select {
+			case ch <- Record{Err: err}:
+			case <-ctx.Done():
+			}
+		}
+	}()
+	return ch
+}
@@ -0,0 +1,58 @@
+package reconcile
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/seuf/zabbix"
+)
+
+// ApplyError is returned by Apply when one of changes failed.
+// Changes applied before it are rolled back in reverse order where possible.
+type ApplyError struct {
+	Change           *Change   // failed change
+	Err              error     // error of failed change
+	Applied          []*Change // changes applied before failed one
+	RolledBack       []*Change // applied changes successfully reverted
+	RollbackFailures []error   // errors of changes which could not be reverted, deletes can never be reverted
+	Skipped          []*Change // changes not attempted
+}
+
+func (e *ApplyError) Error() string {
+	lines := []string{fmt.Sprintf("Failed to %s: %s", e.Change, e.Err)}
+	for _, c := range e.RolledBack {
+		lines = append(lines, fmt.Sprintf("  rolled back: %s", c))
+	}
+	for _, err := range e.RollbackFailures {
+		lines = append(lines, fmt.Sprintf("  not rolled back: %s", err))
+	}
+	for _, c := range e.Skipped {
+		lines = append(lines, fmt.Sprintf("  skipped: %s", c))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Apply makes planned changes in order.
+// If a change fails, already applied changes are rolled back and *ApplyError is returned.
+// Plan should be applied only once.
+func (p *Plan) Apply(api *zabbix.API) (err error) {
+	for i, c := range p.Changes {
+		if err = c.apply(api); err == nil {
+			continue
+		}
+
+		e := &ApplyError{Change: c, Err: err, Applied: p.Changes[:i], Skipped: p.Changes[i+1:]}
+		for j := i - 1; j >= 0; j-- {
+			a := p.Changes[j]
+			if a.rollback == nil {
+				e.RollbackFailures = append(e.RollbackFailures, fmt.Errorf("%s: can't be reverted", a))
+			} else if err := a.rollback(api); err != nil {
+				e.RollbackFailures = append(e.RollbackFailures, fmt.Errorf("%s: %s", a, err))
+			} else {
+				e.RolledBack = append(e.RolledBack, a)
+			}
+		}
+		return e
+	}
+	return
+}
@@ -0,0 +1,1005 @@
+package reconcile
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/seuf/zabbix"
+)
+
+type ChangeType int
+
+const (
+	Create ChangeType = iota
+	Update
+	Delete
+)
+
+func (t ChangeType) String() string {
+	return [...]string{"create", "update", "delete"}[t]
+}
+
+// FieldDiff is a changed field of updated object.
+type FieldDiff struct {
+	Field string
+	Old   string
+	New   string
+}
+
+// Change is a single planned change of one object.
+type Change struct {
+	Type  ChangeType
+	Kind  string // for example, "host" or "item"
+	Name  string
+	Diffs []FieldDiff
+
+	apply    func(api *zabbix.API) error
+	rollback func(api *zabbix.API) error // reverts applied change, nil if it can't be reverted
+}
+
+func (c *Change) String() string {
+	return fmt.Sprintf("%s %s %q", c.Type, c.Kind, c.Name)
+}
+
+// Plan is a list of changes in order they will be applied.
+type Plan struct {
+	Changes []*Change
+}
+
+// Kinds in dependency order: objects of each kind may refer to objects of previous kinds.
+var kinds = []string{"host_group", "template", "host", "item", "trigger", "maintenance", "action"}
+
+// Orders changes: creates and updates in dependency order, then deletes in reverse order.
+func (p *Plan) sort() {
+	rank := func(c *Change) int {
+		for i, k := range kinds {
+			if k == c.Kind {
+				if c.Type == Delete {
+					return 2*len(kinds) - i
+				}
+				return i
+			}
+		}
+		panic("unexpected kind " + c.Kind)
+	}
+	sort.SliceStable(p.Changes, func(i, j int) bool { return rank(p.Changes[i]) < rank(p.Changes[j]) })
+}
+
+// WriteTo writes human-readable plan to w.
+func (p *Plan) WriteTo(w io.Writer) (n int64, err error) {
+	var buf bytes.Buffer
+	if len(p.Changes) == 0 {
+		buf.WriteString("No changes. Zabbix configuration matches desired state.\n")
+	} else {
+		counts := make(map[ChangeType]int)
+		buf.WriteString("Zabbix configuration will be changed as follows:\n\n")
+		for _, c := range p.Changes {
+			counts[c.Type]++
+			fmt.Fprintf(&buf, "  %s %s %q\n", [...]string{"+", "~", "-"}[c.Type], c.Kind, c.Name)
+			for _, d := range c.Diffs {
+				fmt.Fprintf(&buf, "      %s: %s -> %s\n", d.Field, d.Old, d.New)
+			}
+		}
+		fmt.Fprintf(&buf, "\nPlan: %d to create, %d to update, %d to delete.\n", counts[Create], counts[Update], counts[Delete])
+	}
+	return buf.WriteTo(w)
+}
+
+func (p *Plan) String() string {
+	var buf bytes.Buffer
+	p.WriteTo(&buf)
+	return buf.String()
+}
+
+// differ collects field differences.
+type differ []FieldDiff
+
+func (d *differ) str(field, old, new string) {
+	if old != new {
+		*d = append(*d, FieldDiff{field, fmt.Sprintf("%q", old), fmt.Sprintf("%q", new)})
+	}
+}
+
+func (d *differ) int(field string, old, new int64) {
+	if old != new {
+		*d = append(*d, FieldDiff{field, fmt.Sprint(old), fmt.Sprint(new)})
+	}
+}
+
+func (d *differ) set(field string, old, new []string) {
+	old, new = sorted(old), sorted(new)
+	if strings.Join(old, "\x00") != strings.Join(new, "\x00") {
+		*d = append(*d, FieldDiff{field, fmt.Sprintf("%q", old), fmt.Sprintf("%q", new)})
+	}
+}
+
+func sorted(s []string) []string {
+	s = append([]string{}, s...)
+	sort.Strings(s)
+	return s
+}
+
+// Returns elements of a not present in b.
+func missing(a, b []string) (res []string) {
+	m := make(map[string]bool, len(b))
+	for _, s := range b {
+		m[s] = true
+	}
+	for _, s := range a {
+		if !m[s] {
+			res = append(res, s)
+		}
+	}
+	return
+}
+
+// Plan compares desired state with live server and returns changes to make.
+func (s *State) Plan(api *zabbix.API) (p *Plan, err error) {
+	p = new(Plan)
+	for _, f := range []func(*zabbix.API) ([]*Change, error){
+		s.planHostGroups, s.planTemplates, s.planHosts, s.planItems, s.planTriggers, s.planMaintenances, s.planActions,
+	} {
+		var changes []*Change
+		if changes, err = f(api); err != nil {
+			return nil, err
+		}
+		p.Changes = append(p.Changes, changes...)
+	}
+	p.sort()
+	return
+}
+
+func (s *State) planHostGroups(api *zabbix.API) (res []*Change, err error) {
+	for _, g := range s.HostGroups {
+		g := g
+		var live zabbix.HostGroups
+		if live, err = api.HostGroupsGet(zabbix.Params{"filter": zabbix.Params{"name": g.Name}}); err != nil {
+			return
+		}
+
+		switch {
+		case !g.Absent && len(live) == 0:
+			c := &Change{Type: Create, Kind: "host_group", Name: g.Name}
+			c.apply = func(api *zabbix.API) error {
+				groups := zabbix.HostGroups{{Name: g.Name}}
+				if err := api.HostGroupsCreate(groups); err != nil {
+					return err
+				}
+				c.rollback = func(api *zabbix.API) error { return api.HostGroupsDeleteByIds([]string{groups[0].GroupId}) }
+				return nil
+			}
+			res = append(res, c)
+
+		case g.Absent && len(live) > 0:
+			id := live[0].GroupId
+			res = append(res, &Change{Type: Delete, Kind: "host_group", Name: g.Name, apply: func(api *zabbix.API) error {
+				return api.HostGroupsDeleteByIds([]string{id})
+			}})
+		}
+	}
+	return
+}
+
+// Returns host group names of hosts or templates.
+func groupNames(api *zabbix.API, params zabbix.Params) (res []string, err error) {
+	groups, err := api.HostGroupsGet(params)
+	for _, g := range groups {
+		res = append(res, g.Name)
+	}
+	return
+}
+
+// Returns names of templates linked to host or template.
+func linkedTemplateNames(api *zabbix.API, hostId string) (res []string, err error) {
+	templates, err := api.TemplatesGet(zabbix.Params{"hostids": hostId})
+	for _, t := range templates {
+		if t.TemplateId != hostId {
+			res = append(res, t.Host)
+		}
+	}
+	return
+}
+
+// Resolves host group names to IDs.
+func groupIds(api *zabbix.API, names []string) (res zabbix.HostGroupIds, err error) {
+	if len(names) == 0 {
+		return
+	}
+	groups, err := api.HostGroupsGet(zabbix.Params{"filter": zabbix.Params{"name": names}})
+	if err != nil {
+		return
+	}
+	ids := make(map[string]string, len(groups))
+	for _, g := range groups {
+		ids[g.Name] = g.GroupId
+	}
+	for _, name := range names {
+		if ids[name] == "" {
+			return nil, fmt.Errorf("Host group %q not found.", name)
+		}
+		res = append(res, zabbix.HostGroupId{GroupId: ids[name]})
+	}
+	return
+}
+
+// Resolves template names to IDs.
+func templateIds(api *zabbix.API, names []string) (res []string, err error) {
+	if len(names) == 0 {
+		return
+	}
+	templates, err := api.TemplatesGet(zabbix.Params{"filter": zabbix.Params{"host": names}})
+	if err != nil {
+		return
+	}
+	ids := make(map[string]string, len(templates))
+	for _, t := range templates {
+		ids[t.Host] = t.TemplateId
+	}
+	for _, name := range names {
+		if ids[name] == "" {
+			return nil, fmt.Errorf("Template %q not found.", name)
+		}
+		res = append(res, ids[name])
+	}
+	return
+}
+
+// Resolves host or template names to IDs.
+func hostIds(api *zabbix.API, names []string) (res []string, err error) {
+	if len(names) == 0 {
+		return
+	}
+	hosts, err := api.HostsGet(zabbix.Params{"filter": zabbix.Params{"host": names}, "templated_hosts": true})
+	if err != nil {
+		return
+	}
+	ids := make(map[string]string, len(hosts))
+	for _, h := range hosts {
+		ids[h.Host] = h.HostId
+	}
+	for _, name := range names {
+		if ids[name] == "" {
+			return nil, fmt.Errorf("Host %q not found.", name)
+		}
+		res = append(res, ids[name])
+	}
+	return
+}
+
+// Links and unlinks templates of host or template.
+func relink(api *zabbix.API, hostId string, link, unlink []string) (err error) {
+	ids, err := templateIds(api, link)
+	if err == nil && len(ids) > 0 {
+		err = api.TemplatesLinkHosts(ids, []string{hostId})
+	}
+	if err != nil {
+		return
+	}
+	ids, err = templateIds(api, unlink)
+	if err == nil && len(ids) > 0 {
+		err = api.TemplatesUnlinkHosts(ids, []string{hostId}, false)
+	}
+	return
+}
+
+func (s *State) planTemplates(api *zabbix.API) (res []*Change, err error) {
+	for _, t := range s.Templates {
+		t := t
+		var live zabbix.Templates
+		if live, err = api.TemplatesGet(zabbix.Params{"filter": zabbix.Params{"host": t.Host}}); err != nil {
+			return
+		}
+
+		switch {
+		case !t.Absent && len(live) == 0:
+			c := &Change{Type: Create, Kind: "template", Name: t.Host}
+			c.apply = func(api *zabbix.API) error {
+				template := zabbix.Template{Host: t.Host, Name: t.Name, Description: t.Description}
+				var err error
+				if template.GroupIds, err = groupIds(api, t.Groups); err != nil {
+					return err
+				}
+				ids, err := templateIds(api, t.Templates)
+				if err != nil {
+					return err
+				}
+				for _, id := range ids {
+					template.TemplateIds = append(template.TemplateIds, zabbix.TemplateId{TemplateId: id})
+				}
+				templates := zabbix.Templates{template}
+				if err = api.TemplatesCreate(templates); err != nil {
+					return err
+				}
+				c.rollback = func(api *zabbix.API) error { return api.TemplatesDeleteByIds([]string{templates[0].TemplateId}) }
+				return nil
+			}
+			res = append(res, c)
+
+		case !t.Absent:
+			old := live[0]
+			var oldGroups, oldTemplates []string
+			if oldGroups, err = groupNames(api, zabbix.Params{"templateids": old.TemplateId}); err != nil {
+				return
+			}
+			if oldTemplates, err = linkedTemplateNames(api, old.TemplateId); err != nil {
+				return
+			}
+
+			var d differ
+			if t.Name != "" {
+				d.str("name", old.Name, t.Name)
+			}
+			d.str("description", old.Description, t.Description)
+			d.set("groups", oldGroups, t.Groups)
+			d.set("templates", oldTemplates, t.Templates)
+			if len(d) == 0 {
+				continue
+			}
+
+			update := func(api *zabbix.API, name, description string, groups, link, unlink []string) error {
+				template := zabbix.Template{TemplateId: old.TemplateId, Host: old.Host, Name: name, Description: description}
+				var err error
+				if template.GroupIds, err = groupIds(api, groups); err != nil {
+					return err
+				}
+				if err = api.TemplatesUpdate(zabbix.Templates{template}); err != nil {
+					return err
+				}
+				return relink(api, old.TemplateId, link, unlink)
+			}
+			name := t.Name
+			if name == "" {
+				name = old.Name
+			}
+			added, removed := missing(t.Templates, oldTemplates), missing(oldTemplates, t.Templates)
+			res = append(res, &Change{Type: Update, Kind: "template", Name: t.Host, Diffs: d,
+				apply: func(api *zabbix.API) error {
+					return update(api, name, t.Description, t.Groups, added, removed)
+				},
+				rollback: func(api *zabbix.API) error {
+					return update(api, old.Name, old.Description, oldGroups, removed, added)
+				},
+			})
+
+		case t.Absent && len(live) > 0:
+			id := live[0].TemplateId
+			res = append(res, &Change{Type: Delete, Kind: "template", Name: t.Host, apply: func(api *zabbix.API) error {
+				return api.TemplatesDeleteByIds([]string{id})
+			}})
+		}
+	}
+	return
+}
+
+func (s *State) planHosts(api *zabbix.API) (res []*Change, err error) {
+	for _, h := range s.Hosts {
+		h := h
+		var live zabbix.Hosts
+		if live, err = api.HostsGet(zabbix.Params{"filter": zabbix.Params{"host": h.Host}}); err != nil {
+			return
+		}
+
+		switch {
+		case !h.Absent && len(live) == 0:
+			c := &Change{Type: Create, Kind: "host", Name: h.Host}
+			c.apply = func(api *zabbix.API) error {
+				host := zabbix.Host{Host: h.Host, Name: h.Name, Status: hostStatuses[h.Status]}
+				var err error
+				if host.GroupIds, err = groupIds(api, h.Groups); err != nil {
+					return err
+				}
+				ids, err := templateIds(api, h.Templates)
+				if err != nil {
+					return err
+				}
+				for _, id := range ids {
+					host.TemplateIds = append(host.TemplateIds, zabbix.TemplateId{TemplateId: id})
+				}
+				for i, iface := range h.Interfaces {
+					useIP := 0
+					if iface.IP != "" {
+						useIP = 1
+					}
+					main := 0
+					if i == 0 || iface.Type != h.Interfaces[i-1].Type {
+						main = 1
+					}
+					host.Interfaces = append(host.Interfaces, zabbix.HostInterface{
+						DNS: iface.DNS, IP: iface.IP, Port: iface.Port, Type: interfaceTypes[iface.Type], UseIP: useIP, Main: main,
+					})
+				}
+				hosts := zabbix.Hosts{host}
+				if err = api.HostsCreate(hosts); err != nil {
+					return err
+				}
+				c.rollback = func(api *zabbix.API) error { return api.HostsDeleteByIds([]string{hosts[0].HostId}) }
+				return nil
+			}
+			res = append(res, c)
+
+		case !h.Absent:
+			old := live[0]
+			var oldGroups, oldTemplates []string
+			if oldGroups, err = groupNames(api, zabbix.Params{"hostids": old.HostId}); err != nil {
+				return
+			}
+			if oldTemplates, err = linkedTemplateNames(api, old.HostId); err != nil {
+				return
+			}
+
+			var d differ
+			if h.Name != "" {
+				d.str("name", old.Name, h.Name)
+			}
+			d.int("status", int64(old.Status), int64(hostStatuses[h.Status]))
+			d.set("groups", oldGroups, h.Groups)
+			d.set("templates", oldTemplates, h.Templates)
+			if len(d) == 0 {
+				continue
+			}
+
+			update := func(api *zabbix.API, name string, status zabbix.StatusType, groups, link, unlink []string) error {
+				host := zabbix.Host{HostId: old.HostId, Host: old.Host, Name: name, Status: status}
+				var err error
+				if host.GroupIds, err = groupIds(api, groups); err != nil {
+					return err
+				}
+				if err = api.HostsUpdate(zabbix.Hosts{host}); err != nil {
+					return err
+				}
+				return relink(api, old.HostId, link, unlink)
+			}
+			name := h.Name
+			if name == "" {
+				name = old.Name
+			}
+			added, removed := missing(h.Templates, oldTemplates), missing(oldTemplates, h.Templates)
+			res = append(res, &Change{Type: Update, Kind: "host", Name: h.Host, Diffs: d,
+				apply: func(api *zabbix.API) error {
+					return update(api, name, hostStatuses[h.Status], h.Groups, added, removed)
+				},
+				rollback: func(api *zabbix.API) error {
+					return update(api, old.Name, old.Status, oldGroups, removed, added)
+				},
+			})
+
+		case h.Absent && len(live) > 0:
+			id := live[0].HostId
+			res = append(res, &Change{Type: Delete, Kind: "host", Name: h.Host, apply: func(api *zabbix.API) error {
+				return api.HostsDeleteByIds([]string{id})
+			}})
+		}
+	}
+	return
+}
+
+// Returns ID of host or template, or empty string if it doesn't exist yet.
+func findHostId(api *zabbix.API, host string) (id string, err error) {
+	hosts, err := api.HostsGet(zabbix.Params{"filter": zabbix.Params{"host": host}, "templated_hosts": true})
+	if err == nil && len(hosts) > 0 {
+		id = hosts[0].HostId
+	}
+	return
+}
+
+// Returns interface type required by item type, or 0.
+func itemInterfaceType(t zabbix.ItemType) zabbix.InterfaceType {
+	switch t {
+	case zabbix.ZabbixAgent:
+		return zabbix.Agent
+	case zabbix.SNMPv1Agent, zabbix.SNMPv2Agent, zabbix.SNMPv3Agent:
+		return zabbix.SNMP
+	case zabbix.IPMIAgent:
+		return zabbix.IPMI
+	case zabbix.JMXAgent:
+		return zabbix.JMX
+	}
+	return 0
+}
+
+func (s *State) planItems(api *zabbix.API) (res []*Change, err error) {
+	for _, i := range s.Items {
+		i := i
+		name := i.Host + " " + i.Key
+		var hostId string
+		if hostId, err = findHostId(api, i.Host); err != nil {
+			return
+		}
+		var live zabbix.Items
+		if hostId != "" {
+			if live, err = api.ItemsGet(zabbix.Params{"hostids": hostId, "filter": zabbix.Params{"key_": i.Key}}); err != nil {
+				return
+			}
+		}
+
+		desired := zabbix.Item{
+			Key: i.Key, Name: i.Name, Type: itemTypes[i.Type], ValueType: valueTypes[i.ValueType],
+			Delay: i.Delay, History: i.History, Trends: i.Trends, Description: i.Description,
+		}
+
+		switch {
+		case !i.Absent && len(live) == 0:
+			if i.Type == "" || i.ValueType == "" {
+				return nil, fmt.Errorf("Item %q can't be created without type and value type.", name)
+			}
+			c := &Change{Type: Create, Kind: "item", Name: name}
+			c.apply = func(api *zabbix.API) error {
+				item := desired
+				ids, err := hostIds(api, []string{i.Host})
+				if err != nil {
+					return err
+				}
+				item.HostId = ids[0]
+				if t := itemInterfaceType(item.Type); t != 0 {
+					ifaces, err := api.HostInterfacesGet(zabbix.Params{"hostids": item.HostId, "filter": zabbix.Params{"main": 1, "type": t}})
+					if err != nil {
+						return err
+					}
+					if len(ifaces) > 0 {
+						item.InterfaceId = ifaces[0].InterfaceId
+					}
+				}
+				items := zabbix.Items{item}
+				if err = api.ItemsCreate(items); err != nil {
+					return err
+				}
+				c.rollback = func(api *zabbix.API) error { return api.ItemsDeleteByIds([]string{items[0].ItemId}) }
+				return nil
+			}
+			res = append(res, c)
+
+		case !i.Absent:
+			// fields which are not set keep live values
+			old := live[0]
+			old.TriggersIds, old.Error = nil, ""
+			update := old
+			var d differ
+			if i.Name != "" {
+				d.str("name", old.Name, i.Name)
+				update.Name = i.Name
+			}
+			if i.Type != "" {
+				update.Type = itemTypes[i.Type]
+				d.int("type", int64(old.Type), int64(update.Type))
+			}
+			if i.ValueType != "" {
+				update.ValueType = valueTypes[i.ValueType]
+				d.int("value_type", int64(old.ValueType), int64(update.ValueType))
+			}
+			if i.Delay != "" {
+				d.str("delay", old.Delay, i.Delay)
+				update.Delay = i.Delay
+			}
+			if i.History != "" {
+				d.str("history", old.History, i.History)
+				update.History = i.History
+			}
+			if i.Trends != "" {
+				d.str("trends", old.Trends, i.Trends)
+				update.Trends = i.Trends
+			}
+			if i.Description != "" {
+				d.str("description", old.Description, i.Description)
+				update.Description = i.Description
+			}
+			if len(d) == 0 {
+				continue
+			}
+
+			res = append(res, &Change{Type: Update, Kind: "item", Name: name, Diffs: d,
+				apply:    func(api *zabbix.API) error { return api.ItemsUpdate(zabbix.Items{update}) },
+				rollback: func(api *zabbix.API) error { return api.ItemsUpdate(zabbix.Items{old}) },
+			})
+
+		case i.Absent && len(live) > 0:
+			id := live[0].ItemId
+			res = append(res, &Change{Type: Delete, Kind: "item", Name: name, apply: func(api *zabbix.API) error {
+				return api.ItemsDeleteByIds([]string{id})
+			}})
+		}
+	}
+	return
+}
+
+func (s *State) planTriggers(api *zabbix.API) (res []*Change, err error) {
+	for _, t := range s.Triggers {
+		t := t
+		name := t.Host + " " + t.Description
+		var hostId string
+		if hostId, err = findHostId(api, t.Host); err != nil {
+			return
+		}
+		var live zabbix.Triggers
+		if hostId != "" {
+			params := zabbix.Params{"hostids": hostId, "filter": zabbix.Params{"description": t.Description}, "expandExpression": true}
+			if live, err = api.TriggersGet(params); err != nil {
+				return
+			}
+		}
+
+		desired := zabbix.Trigger{Description: t.Description, Expression: t.Expression, Priority: priorities[t.Priority]}
+
+		switch {
+		case !t.Absent && len(live) == 0:
+			c := &Change{Type: Create, Kind: "trigger", Name: name}
+			c.apply = func(api *zabbix.API) error {
+				triggers := zabbix.Triggers{desired}
+				if err := api.TriggersCreate(triggers); err != nil {
+					return err
+				}
+				c.rollback = func(api *zabbix.API) error { return api.TriggersDeleteByIds([]string{triggers[0].TriggerId}) }
+				return nil
+			}
+			res = append(res, c)
+
+		case !t.Absent:
+			old := live[0]
+			var d differ
+			d.str("expression", old.Expression, desired.Expression)
+			d.int("priority", int64(old.Priority), int64(desired.Priority))
+			if len(d) == 0 {
+				continue
+			}
+
+			desired.TriggerId = old.TriggerId
+			old = zabbix.Trigger{TriggerId: old.TriggerId, Description: old.Description, Expression: old.Expression, Priority: old.Priority}
+			res = append(res, &Change{Type: Update, Kind: "trigger", Name: name, Diffs: d,
+				apply:    func(api *zabbix.API) error { return api.TriggersUpdate(zabbix.Triggers{desired}) },
+				rollback: func(api *zabbix.API) error { return api.TriggersUpdate(zabbix.Triggers{old}) },
+			})
+
+		case t.Absent && len(live) > 0:
+			id := live[0].TriggerId
+			res = append(res, &Change{Type: Delete, Kind: "trigger", Name: name, apply: func(api *zabbix.API) error {
+				return api.TriggersDeleteByIds([]string{id})
+			}})
+		}
+	}
+	return
+}
+
+// Formats time period for comparison and plan output.
+func formatTimePeriod(p zabbix.TimePeriod) string {
+	return fmt.Sprintf("type=%d every=%d dayofweek=%d day=%s month=%d start_time=%d period=%d start_date=%d",
+		p.TimePeriodType, p.Every, p.DayOfWeek, p.Day, p.Month, p.StartTime, p.Period, p.StartDate)
+}
+
+func (m *Maintenance) timePeriods() (res zabbix.TimePeriods) {
+	for _, p := range m.TimePeriods {
+		period := zabbix.TimePeriod{
+			TimePeriodType: periodTypes[p.Type], Every: p.Every, DayOfWeek: p.DayOfWeek, Month: p.Month,
+			StartTime: p.StartTime, Period: p.Period,
+		}
+		if p.Day != 0 {
+			period.Day = fmt.Sprint(p.Day)
+		}
+		if !p.StartDate.IsZero() {
+			period.StartDate = p.StartDate.Unix()
+		}
+		res = append(res, period)
+	}
+	return
+}
+
+func formatTime(t int64) string {
+	if t == 0 {
+		return "-"
+	}
+	return time.Unix(t, 0).UTC().Format(time.RFC3339)
+}
+
+func (s *State) planMaintenances(api *zabbix.API) (res []*Change, err error) {
+	for _, m := range s.Maintenances {
+		m := m
+		var live zabbix.Maintenances
+		if live, err = api.MaintenancesGet(zabbix.Params{"filter": zabbix.Params{"name": m.Name}}); err != nil {
+			return
+		}
+
+		desired := zabbix.Maintenance{
+			Name: m.Name, Description: m.Description, MaintenanceType: maintenanceTypes[m.Type], TimePeriods: m.timePeriods(),
+		}
+		if !m.ActiveSince.IsZero() {
+			desired.ActiveSince = m.ActiveSince.Unix()
+		}
+		if !m.ActiveTill.IsZero() {
+			desired.ActiveTill = m.ActiveTill.Unix()
+		}
+		// resolves host and group names at apply time, as they may be created by the same plan
+		resolve := func(api *zabbix.API, maintenance zabbix.Maintenance, hosts, groups []string) (zabbix.Maintenance, error) {
+			var err error
+			if maintenance.HostIDs, err = hostIds(api, hosts); err != nil {
+				return maintenance, err
+			}
+			ids, err := groupIds(api, groups)
+			for _, id := range ids {
+				maintenance.HostGroupIDs = append(maintenance.HostGroupIDs, id.GroupId)
+			}
+			if maintenance.HostIDs == nil {
+				maintenance.HostIDs = []string{}
+			}
+			if maintenance.HostGroupIDs == nil {
+				maintenance.HostGroupIDs = []string{}
+			}
+			return maintenance, err
+		}
+
+		switch {
+		case !m.Absent && len(live) == 0:
+			c := &Change{Type: Create, Kind: "maintenance", Name: m.Name}
+			c.apply = func(api *zabbix.API) error {
+				maintenance, err := resolve(api, desired, m.Hosts, m.Groups)
+				if err != nil {
+					return err
+				}
+				maintenances := zabbix.Maintenances{maintenance}
+				if err = api.MaintenancesCreate(maintenances); err != nil {
+					return err
+				}
+				c.rollback = func(api *zabbix.API) error {
+					return api.MaintenancesDeleteByIDs([]string{maintenances[0].MaintenanceID})
+				}
+				return nil
+			}
+			res = append(res, c)
+
+		case !m.Absent:
+			old := live[0]
+			var oldHosts, oldGroups, oldPeriods, newPeriods []string
+			for _, h := range old.Hosts {
+				oldHosts = append(oldHosts, h.Host)
+			}
+			for _, g := range old.HostGroups {
+				oldGroups = append(oldGroups, g.Name)
+			}
+			for _, p := range old.TimePeriods {
+				p.TimePeriodID = ""
+				oldPeriods = append(oldPeriods, formatTimePeriod(p))
+			}
+			for _, p := range desired.TimePeriods {
+				newPeriods = append(newPeriods, formatTimePeriod(p))
+			}
+
+			var d differ
+			d.str("description", old.Description, desired.Description)
+			d.int("maintenance_type", int64(old.MaintenanceType), int64(desired.MaintenanceType))
+			if desired.ActiveSince != 0 && old.ActiveSince != desired.ActiveSince {
+				d.str("active_since", formatTime(old.ActiveSince), formatTime(desired.ActiveSince))
+			}
+			if desired.ActiveTill != 0 && old.ActiveTill != desired.ActiveTill {
+				d.str("active_till", formatTime(old.ActiveTill), formatTime(desired.ActiveTill))
+			}
+			d.set("hosts", oldHosts, m.Hosts)
+			d.set("groups", oldGroups, m.Groups)
+			d.set("timeperiods", oldPeriods, newPeriods)
+			if len(d) == 0 {
+				continue
+			}
+
+			// tags are not managed, so live ones are kept
+			desired.MaintenanceID, desired.Tags, desired.TagsEvalType = old.MaintenanceID, old.Tags, old.TagsEvalType
+			if desired.ActiveSince == 0 {
+				desired.ActiveSince = old.ActiveSince
+			}
+			if desired.ActiveTill == 0 {
+				desired.ActiveTill = old.ActiveTill
+			}
+			for i := range old.TimePeriods {
+				old.TimePeriods[i].TimePeriodID = ""
+			}
+			previous := zabbix.Maintenance{
+				MaintenanceID: old.MaintenanceID, Name: old.Name, Description: old.Description, MaintenanceType: old.MaintenanceType,
+				ActiveSince: old.ActiveSince, ActiveTill: old.ActiveTill, TimePeriods: old.TimePeriods,
+				Tags: old.Tags, TagsEvalType: old.TagsEvalType,
+			}
+			res = append(res, &Change{Type: Update, Kind: "maintenance", Name: m.Name, Diffs: d,
+				apply: func(api *zabbix.API) error {
+					maintenance, err := resolve(api, desired, m.Hosts, m.Groups)
+					if err == nil {
+						err = api.MaintenancesUpdate(zabbix.Maintenances{maintenance})
+					}
+					return err
+				},
+				rollback: func(api *zabbix.API) error {
+					maintenance, err := resolve(api, previous, oldHosts, oldGroups)
+					if err == nil {
+						err = api.MaintenancesUpdate(zabbix.Maintenances{maintenance})
+					}
+					return err
+				},
+			})
+
+		case m.Absent && len(live) > 0:
+			id := live[0].MaintenanceID
+			res = append(res, &Change{Type: Delete, Kind: "maintenance", Name: m.Name, apply: func(api *zabbix.API) error {
+				return api.MaintenancesDeleteByIDs([]string{id})
+			}})
+		}
+	}
+	return
+}
+
+// Converts value to JSON representation: maps, slices, strings, float64 and bools.
+func normalize(v interface{}) (res interface{}, err error) {
+	b, err := json.Marshal(v)
+	if err == nil {
+		err = json.Unmarshal(b, &res)
+	}
+	return
+}
+
+// Checks that all fields of desired are present in live with the same values.
+// Zabbix returns numbers as strings, so scalars are compared as strings, and missing value is equal to zero.
+func subset(desired, live interface{}) bool {
+	switch d := desired.(type) {
+	case map[string]interface{}:
+		l, _ := live.(map[string]interface{})
+		for k, v := range d {
+			if !subset(v, l[k]) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		l, _ := live.([]interface{})
+		if len(d) != len(l) {
+			return false
+		}
+		for i := range d {
+			if !subset(d[i], l[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		if live == nil {
+			s := fmt.Sprint(desired)
+			return s == "" || s == "0" || s == "false"
+		}
+		return fmt.Sprint(desired) == fmt.Sprint(live)
+	}
+}
+
+func formatJSON(v interface{}) string {
+	if v == nil {
+		return "-"
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+func (s *State) planActions(api *zabbix.API) (res []*Change, err error) {
+	for _, a := range s.Actions {
+		a := a
+		var live zabbix.Actions
+		params := zabbix.Params{"filter": zabbix.Params{"name": a.Name}}
+		if live, err = api.ActionGet(params); err != nil {
+			return
+		}
+
+		var n interface{}
+		if n, err = normalize(a.Spec); err != nil {
+			return
+		}
+		spec, _ := n.(map[string]interface{})
+		if spec == nil {
+			spec = make(map[string]interface{})
+		}
+		spec["name"] = a.Name
+
+		switch {
+		case !a.Absent && len(live) == 0:
+			c := &Change{Type: Create, Kind: "action", Name: a.Name}
+			c.apply = func(api *zabbix.API) error {
+				id, err := createAction(api, spec)
+				if err != nil {
+					return err
+				}
+				c.rollback = func(api *zabbix.API) error { return api.ActionsDeleteByIds([]string{id}) }
+				return nil
+			}
+			res = append(res, c)
+
+		case !a.Absent:
+			id := live[0].ActionId
+			var l interface{}
+			if l, err = normalize(live[0]); err != nil {
+				return
+			}
+			old, _ := l.(map[string]interface{})
+
+			var d differ
+			update, previous := zabbix.Params{"actionid": id}, zabbix.Params{"actionid": id}
+			for _, k := range sortedKeys(spec) {
+				if !subset(spec[k], old[k]) {
+					d = append(d, FieldDiff{k, formatJSON(old[k]), formatJSON(spec[k])})
+					update[k] = spec[k]
+					if old[k] != nil {
+						previous[k] = old[k]
+					}
+				}
+			}
+			if len(d) == 0 {
+				continue
+			}
+
+			res = append(res, &Change{Type: Update, Kind: "action", Name: a.Name, Diffs: d,
+				apply:    func(api *zabbix.API) error { return updateAction(api, update) },
+				rollback: func(api *zabbix.API) error { return updateAction(api, previous) },
+			})
+
+		case a.Absent && len(live) > 0:
+			id := live[0].ActionId
+			res = append(res, &Change{Type: Delete, Kind: "action", Name: a.Name, apply: func(api *zabbix.API) error {
+				return api.ActionsDeleteByIds([]string{id})
+			}})
+		}
+	}
+	return
+}
+
+func sortedKeys(m map[string]interface{}) (res []string) {
+	for k := range m {
+		res = append(res, k)
+	}
+	sort.Strings(res)
+	return
+}
+
+// Validates action in action.create format and creates it as is, as zabbix.Action doesn't have all fields.
+func createAction(api *zabbix.API, params map[string]interface{}) (id string, err error) {
+	b, err := json.Marshal(params)
+	if err != nil {
+		return
+	}
+	var action zabbix.Action
+	if err = json.Unmarshal(b, &action); err != nil {
+		return
+	}
+	if err = action.Validate(); err != nil {
+		return
+	}
+
+	response, err := api.CallWithError("action.create", []interface{}{params})
+	if err != nil {
+		return
+	}
+	result, _ := response.Result.(map[string]interface{})
+	ids, _ := result["actionids"].([]interface{})
+	if len(ids) != 1 {
+		return "", &zabbix.ExpectedMore{Expected: 1, Got: len(ids)}
+	}
+	return fmt.Sprint(ids[0]), nil
+}
+
+// Updates only given fields of action, without read-only ones.
+func updateAction(api *zabbix.API, params zabbix.Params) error {
+	update := make(zabbix.Params, len(params))
+	for k, v := range params {
+		update[k] = v
+	}
+	delete(update, "eventsource")
+	if f, ok := update["filter"].(map[string]interface{}); ok {
+		filter := make(map[string]interface{}, len(f))
+		for k, v := range f {
+			filter[k] = v
+		}
+		delete(filter, "eval_formula")
+		update["filter"] = filter
+	}
+
+	response, err := api.CallWithError("action.update", []interface{}{update})
+	if err != nil {
+		return err
+	}
+	result, _ := response.Result.(map[string]interface{})
+	if ids, _ := result["actionids"].([]interface{}); len(ids) != 1 {
+		return &zabbix.ExpectedMore{Expected: 1, Got: len(ids)}
+	}
+	return nil
+}
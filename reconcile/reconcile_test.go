@@ -0,0 +1,362 @@
+package reconcile_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "."
+	"github.com/seuf/zabbix"
+)
+
+// fakeServer answers JSON-RPC requests with results of handlers by method.
+// Methods without handler return empty array.
+type fakeServer struct {
+	handlers map[string]func(params map[string]interface{}) (interface{}, *zabbix.Error)
+	calls    []string
+	sent     map[string][]interface{} // params of calls by method
+}
+
+func (f *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Method string      `json:"method"`
+		Params interface{} `json:"params"`
+		Id     int32       `json:"id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	f.calls = append(f.calls, req.Method)
+	f.sent[req.Method] = append(f.sent[req.Method], req.Params)
+
+	res := map[string]interface{}{"jsonrpc": "2.0", "id": req.Id, "result": []interface{}{}}
+	if h := f.handlers[req.Method]; h != nil {
+		params, _ := req.Params.(map[string]interface{}) // array params are not inspected
+		result, err := h(params)
+		if err != nil {
+			delete(res, "result")
+			res["error"] = err
+		} else {
+			res["result"] = result
+		}
+	}
+	json.NewEncoder(w).Encode(res)
+}
+
+func newFakeServer(t *testing.T) (*fakeServer, *zabbix.API) {
+	f := &fakeServer{
+		handlers: make(map[string]func(map[string]interface{}) (interface{}, *zabbix.Error)),
+		sent:     make(map[string][]interface{}),
+	}
+	s := httptest.NewServer(f)
+	t.Cleanup(s.Close)
+	return f, zabbix.NewAPI(s.URL)
+}
+
+const state = `
+host_groups:
+  - name: Web servers
+  - name: Old servers
+    absent: true
+hosts:
+  - host: web-1
+    groups: [Web servers]
+    interfaces:
+      - {type: agent, ip: 10.0.0.1, port: "10050"}
+  - host: web-2
+    status: unmonitored
+    groups: [Web servers]
+`
+
+func TestLoad(t *testing.T) {
+	s, err := Load(strings.NewReader(state))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.HostGroups) != 2 || !s.HostGroups[1].Absent || len(s.Hosts) != 2 || s.Hosts[0].Interfaces[0].IP != "10.0.0.1" {
+		t.Errorf("Unexpected state: %#v", s)
+	}
+
+	for _, bad := range []string{
+		"host_groups: [{}]",
+		"hosts: [{host: a}]",
+		"hosts: [{host: a, groups: [g], status: broken}]",
+		"items: [{host: a, key: b, type: nope, value_type: float}]",
+		"triggers: [{host: a, description: b}]",
+		"unknown: 1",
+	} {
+		if _, err = Load(strings.NewReader(bad)); err == nil {
+			t.Errorf("%s: expected error", bad)
+		}
+	}
+}
+
+func TestPlanApply(t *testing.T) {
+	f, api := newFakeServer(t)
+	f.handlers["hostgroup.get"] = func(params map[string]interface{}) (interface{}, *zabbix.Error) {
+		filter, _ := params["filter"].(map[string]interface{})
+		res := []interface{}{}
+		switch name := filter["name"].(type) {
+		case string:
+			if name == "Old servers" {
+				res = append(res, map[string]interface{}{"groupid": "7", "name": name})
+			}
+		case []interface{}:
+			// created group
+			res = append(res, map[string]interface{}{"groupid": "8", "name": "Web servers"})
+		}
+		if params["hostids"] == "10" {
+			res = append(res, map[string]interface{}{"groupid": "8", "name": "Web servers"})
+		}
+		return res, nil
+	}
+	f.handlers["host.get"] = func(params map[string]interface{}) (interface{}, *zabbix.Error) {
+		filter, _ := params["filter"].(map[string]interface{})
+		if filter["host"] == "web-2" {
+			return []interface{}{map[string]interface{}{"hostid": "10", "host": "web-2", "name": "web-2", "status": "0"}}, nil
+		}
+		return []interface{}{}, nil
+	}
+	f.handlers["hostgroup.create"] = func(params map[string]interface{}) (interface{}, *zabbix.Error) {
+		return map[string]interface{}{"groupids": []interface{}{"8"}}, nil
+	}
+	f.handlers["hostgroup.delete"] = func(params map[string]interface{}) (interface{}, *zabbix.Error) {
+		return map[string]interface{}{"groupids": []interface{}{"8"}}, nil
+	}
+	f.handlers["host.create"] = func(params map[string]interface{}) (interface{}, *zabbix.Error) {
+		return nil, &zabbix.Error{Code: -32602, Message: "Invalid params.", Data: "Host already exists."}
+	}
+
+	s, err := Load(strings.NewReader(state))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := s.Plan(api)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `Zabbix configuration will be changed as follows:
+
+  + host_group "Web servers"
+  + host "web-1"
+  ~ host "web-2"
+      status: 0 -> 1
+  - host_group "Old servers"
+
+Plan: 2 to create, 1 to update, 1 to delete.
+`
+	if p.String() != expected {
+		t.Errorf("Unexpected plan:\n%s", p)
+	}
+
+	err = p.Apply(api)
+	e, ok := err.(*ApplyError)
+	if !ok {
+		t.Fatalf("Expected *ApplyError, got %#v", err)
+	}
+	if e.Change != p.Changes[1] || len(e.Applied) != 1 || len(e.RolledBack) != 1 || len(e.RollbackFailures) != 0 || len(e.Skipped) != 2 {
+		t.Errorf("Unexpected error: %s", e)
+	}
+	if f.calls[len(f.calls)-1] != "hostgroup.delete" {
+		t.Errorf("Created host group is not deleted: %v", f.calls)
+	}
+	t.Log(e)
+}
+
+func TestPlanNoChanges(t *testing.T) {
+	_, api := newFakeServer(t)
+	s, err := Load(strings.NewReader("host_groups: [{name: Gone, absent: true}]"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := s.Plan(api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Changes) != 0 || !strings.HasPrefix(p.String(), "No changes.") {
+		t.Errorf("Unexpected plan:\n%s", p)
+	}
+	if err = p.Apply(api); err != nil {
+		t.Error(err)
+	}
+}
+
+// Returns handler with fixed result.
+func result(res interface{}) func(map[string]interface{}) (interface{}, *zabbix.Error) {
+	return func(map[string]interface{}) (interface{}, *zabbix.Error) { return res, nil }
+}
+
+// Returns the only object of array params sent by call i of method.
+func sentObject(t *testing.T, f *fakeServer, method string, i int) map[string]interface{} {
+	if len(f.sent[method]) <= i {
+		t.Fatalf("Expected %d calls of %s, got %d", i+1, method, len(f.sent[method]))
+	}
+	objects, _ := f.sent[method][i].([]interface{})
+	if len(objects) != 1 {
+		t.Fatalf("Unexpected %s params: %#v", method, f.sent[method][i])
+	}
+	return objects[0].(map[string]interface{})
+}
+
+const objectsState = `
+items:
+  - {host: web-1, key: agent.ping, name: Ping, delay: 1m}
+triggers:
+  - {host: web-1, description: Down, expression: "{web-1:agent.ping.nodata(5m)}=1", priority: high}
+maintenances:
+  - name: Backup
+    hosts: [web-1]
+    timeperiods: [{type: daily, every: 1, start_time: 3600, period: 7200}]
+actions:
+  - name: Notify
+    eventsource: 0
+    operations: [{operationtype: 0, opmessage: {default_msg: 1}, opmessage_grp: [{usrgrpid: "7"}]}]
+`
+
+func TestPlanApplyObjects(t *testing.T) {
+	f, api := newFakeServer(t)
+	f.handlers["APIInfo.version"] = result("5.0.0")
+	f.handlers["host.get"] = result([]interface{}{map[string]interface{}{"hostid": "10", "host": "web-1"}})
+	f.handlers["item.get"] = result([]interface{}{map[string]interface{}{
+		"itemid": "11", "hostid": "10", "key_": "agent.ping", "name": "Old ping", "type": "0", "value_type": "3",
+		"delay": "1m", "history": "90d", "trends": "365d", "description": "Availability",
+	}})
+	f.handlers["maintenance.get"] = result([]interface{}{map[string]interface{}{
+		"maintenanceid": "30", "name": "Backup", "maintenance_type": "0", "active_since": "1600000000", "active_till": "1700000000",
+		"hosts": []interface{}{}, "groups": []interface{}{},
+		"timeperiods": []interface{}{map[string]interface{}{
+			"timeperiodid": "31", "timeperiod_type": "2", "every": "1", "start_time": "3600", "period": "3600",
+		}},
+	}})
+	f.handlers["item.update"] = result(map[string]interface{}{"itemids": []interface{}{"11"}})
+	f.handlers["trigger.create"] = result(map[string]interface{}{"triggerids": []interface{}{"20"}})
+	f.handlers["maintenance.update"] = result(map[string]interface{}{"maintenanceids": []interface{}{"30"}})
+	f.handlers["action.create"] = result(map[string]interface{}{"actionids": []interface{}{"40"}})
+
+	s, err := Load(strings.NewReader(objectsState))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := s.Plan(api)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `Zabbix configuration will be changed as follows:
+
+  ~ item "web-1 agent.ping"
+      name: "Old ping" -> "Ping"
+  + trigger "web-1 Down"
+  ~ maintenance "Backup"
+      hosts: [] -> ["web-1"]
+      timeperiods: ["type=2 every=1 dayofweek=0 day= month=0 start_time=3600 period=3600 start_date=0"] -> ["type=2 every=1 dayofweek=0 day= month=0 start_time=3600 period=7200 start_date=0"]
+  + action "Notify"
+
+Plan: 2 to create, 2 to update, 0 to delete.
+`
+	if p.String() != expected {
+		t.Errorf("Unexpected plan:\n%s", p)
+	}
+
+	if err = p.Apply(api); err != nil {
+		t.Fatal(err)
+	}
+	if item := sentObject(t, f, "item.update", 0); item["itemid"] != "11" || item["name"] != "Ping" || item["history"] != "90d" || item["type"] != 0.0 || item["description"] != "Availability" {
+		t.Errorf("Unexpected updated item: %#v", item)
+	}
+	if trigger := sentObject(t, f, "trigger.create", 0); trigger["expression"] != "{web-1:agent.ping.nodata(5m)}=1" || trigger["priority"] != 4.0 {
+		t.Errorf("Unexpected created trigger: %#v", trigger)
+	}
+	m := sentObject(t, f, "maintenance.update", 0)
+	if hosts, _ := m["hostids"].([]interface{}); m["maintenanceid"] != "30" || len(hosts) != 1 || hosts[0] != "10" || m["active_till"] != 1700000000.0 {
+		t.Errorf("Unexpected updated maintenance: %#v", m)
+	}
+	if action := sentObject(t, f, "action.create", 0); action["name"] != "Notify" || len(action["operations"].([]interface{})) != 1 {
+		t.Errorf("Unexpected created action: %#v", action)
+	}
+}
+
+const actionState = `
+host_groups:
+  - {name: Old servers, absent: true}
+actions:
+  - name: Notify
+    pause_suppressed: 0
+    notify_if_canceled: 1
+    filter:
+      evaltype: 0
+      conditions: [{conditiontype: 4, operator: 5, value: "4"}]
+`
+
+func TestPlanItemWithoutType(t *testing.T) {
+	_, api := newFakeServer(t)
+	s, err := Load(strings.NewReader("items: [{host: web-1, key: agent.ping, delay: 1m}]"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = s.Plan(api); err == nil {
+		t.Error("Expected error for new item without type")
+	}
+}
+
+func TestPlanApplyActionUpdate(t *testing.T) {
+	f, api := newFakeServer(t)
+	f.handlers["APIInfo.version"] = result("5.0.0")
+	f.handlers["action.get"] = result([]interface{}{map[string]interface{}{
+		"actionid": "40", "name": "Notify", "eventsource": "0", "status": "0", "esc_period": "1h", "pause_suppressed": "1",
+		"filter": map[string]interface{}{
+			"evaltype": "0", "formula": "", "eval_formula": "A",
+			"conditions": []interface{}{map[string]interface{}{"conditionid": "1", "conditiontype": "4", "operator": "5", "value": "3"}},
+		},
+		"operations": []interface{}{map[string]interface{}{"operationid": "41", "operationtype": "0"}},
+	}})
+	f.handlers["action.update"] = result(map[string]interface{}{"actionids": []interface{}{"40"}})
+	f.handlers["hostgroup.get"] = result([]interface{}{map[string]interface{}{"groupid": "7", "name": "Old servers"}})
+	f.handlers["hostgroup.delete"] = func(map[string]interface{}) (interface{}, *zabbix.Error) {
+		return nil, &zabbix.Error{Code: -32500, Message: "Application error.", Data: "Group is used by host."}
+	}
+
+	s, err := Load(strings.NewReader(actionState))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := s.Plan(api)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `Zabbix configuration will be changed as follows:
+
+  ~ action "Notify"
+      filter: {"conditions":[{"conditionid":"1","conditiontype":4,"operator":5,"value":"3"}],"eval_formula":"A","evaltype":0} -> {"conditions":[{"conditiontype":4,"operator":5,"value":"4"}],"evaltype":0}
+      notify_if_canceled: - -> 1
+      pause_suppressed: 1 -> 0
+  - host_group "Old servers"
+
+Plan: 0 to create, 1 to update, 1 to delete.
+`
+	if p.String() != expected {
+		t.Errorf("Unexpected plan:\n%s", p)
+	}
+
+	// failed delete of host group reverts the action update
+	if _, ok := p.Apply(api).(*ApplyError); !ok {
+		t.Fatal("Expected *ApplyError")
+	}
+	update := sentObject(t, f, "action.update", 0)
+	filter, _ := update["filter"].(map[string]interface{})
+	if len(update) != 4 || update["actionid"] != "40" || update["pause_suppressed"] != 0.0 || update["notify_if_canceled"] != 1.0 ||
+		filter == nil || filter["conditions"].([]interface{})[0].(map[string]interface{})["value"] != "4" {
+		t.Errorf("Unexpected update: %#v", update)
+	}
+	rollback := sentObject(t, f, "action.update", 1)
+	filter, _ = rollback["filter"].(map[string]interface{})
+	if len(rollback) != 3 || rollback["pause_suppressed"] != 1.0 || filter == nil || filter["eval_formula"] != nil ||
+		filter["conditions"].([]interface{})[0].(map[string]interface{})["value"] != "3" {
+		t.Errorf("Unexpected rollback: %#v", rollback)
+	}
+}
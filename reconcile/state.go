@@ -0,0 +1,313 @@
+// Package reconcile brings Zabbix configuration to desired state described in YAML,
+// like Terraform does: Plan compares desired state with live server and Apply makes changes.
+//
+// Objects are matched by names: host groups and maintenances by name, hosts and templates by
+// technical name, items by host and key, triggers by host and description, actions by name.
+// Objects not mentioned in desired state are left untouched; objects marked with `absent: true`
+// are deleted.
+//
+// Example:
+//
+//	host_groups:
+//	  - name: Web servers
+//	hosts:
+//	  - host: web-1
+//	    groups: [Web servers]
+//	    templates: [Template OS Linux]
+//	    interfaces:
+//	      - {type: agent, ip: 10.0.0.1, port: "10050"}
+//	items:
+//	  - {host: web-1, key: deploy.version, name: Deployed version, type: trapper, value_type: character}
+//	triggers:
+//	  - {host: web-1, description: Nginx is down, expression: "{web-1:proc.num[nginx].last()}=0", priority: high}
+package reconcile
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/seuf/zabbix"
+	"gopkg.in/yaml.v3"
+)
+
+// State is a desired state of Zabbix configuration.
+type State struct {
+	HostGroups   []HostGroup   `yaml:"host_groups,omitempty"`
+	Templates    []Template    `yaml:"templates,omitempty"`
+	Hosts        []Host        `yaml:"hosts,omitempty"`
+	Items        []Item        `yaml:"items,omitempty"`
+	Triggers     []Trigger     `yaml:"triggers,omitempty"`
+	Maintenances []Maintenance `yaml:"maintenances,omitempty"`
+	Actions      []Action      `yaml:"actions,omitempty"`
+}
+
+type HostGroup struct {
+	Name   string `yaml:"name"`
+	Absent bool   `yaml:"absent,omitempty"`
+}
+
+type Template struct {
+	Host        string   `yaml:"host"`
+	Name        string   `yaml:"name,omitempty"`
+	Description string   `yaml:"description,omitempty"`
+	Groups      []string `yaml:"groups,omitempty"`    // host group names
+	Templates   []string `yaml:"templates,omitempty"` // linked template names
+	Absent      bool     `yaml:"absent,omitempty"`
+}
+
+// Interface is used only when host is created.
+type Interface struct {
+	Type string `yaml:"type"` // agent, snmp, ipmi or jmx
+	IP   string `yaml:"ip,omitempty"`
+	DNS  string `yaml:"dns,omitempty"`
+	Port string `yaml:"port"`
+}
+
+type Host struct {
+	Host       string      `yaml:"host"`
+	Name       string      `yaml:"name,omitempty"`
+	Status     string      `yaml:"status,omitempty"`    // monitored (default) or unmonitored
+	Groups     []string    `yaml:"groups,omitempty"`    // host group names
+	Templates  []string    `yaml:"templates,omitempty"` // linked template names
+	Interfaces []Interface `yaml:"interfaces,omitempty"`
+	Absent     bool        `yaml:"absent,omitempty"`
+}
+
+// Item fields which are not set are not compared with live item, but type and value type are required to create it.
+type Item struct {
+	Host        string `yaml:"host"` // host or template
+	Key         string `yaml:"key"`
+	Name        string `yaml:"name,omitempty"`
+	Type        string `yaml:"type,omitempty"`       // for example, agent or trapper, see itemTypes
+	ValueType   string `yaml:"value_type,omitempty"` // float, character, log, unsigned or text
+	Delay       string `yaml:"delay,omitempty"`      // for example, "1m", or seconds before Zabbix 3.4
+	History     string `yaml:"history,omitempty"`    // for example, "90d", or days before Zabbix 3.4
+	Trends      string `yaml:"trends,omitempty"`     // for example, "365d", or days before Zabbix 3.4
+	Description string `yaml:"description,omitempty"`
+	Absent      bool   `yaml:"absent,omitempty"`
+}
+
+type Trigger struct {
+	Host        string `yaml:"host"` // host or template
+	Description string `yaml:"description"`
+	Expression  string `yaml:"expression,omitempty"`
+	Priority    string `yaml:"priority,omitempty"` // not_classified, information, warning, average, high or disaster
+	Absent      bool   `yaml:"absent,omitempty"`
+}
+
+type TimePeriod struct {
+	Type      string    `yaml:"type"` // one_time, daily, weekly or monthly
+	Every     int       `yaml:"every,omitempty"`
+	DayOfWeek int       `yaml:"dayofweek,omitempty"`
+	Day       int       `yaml:"day,omitempty"`
+	Month     int       `yaml:"month,omitempty"`
+	StartTime int64     `yaml:"start_time,omitempty"`
+	Period    int64     `yaml:"period,omitempty"`
+	StartDate time.Time `yaml:"start_date,omitempty"`
+}
+
+type Maintenance struct {
+	Name        string       `yaml:"name"`
+	Description string       `yaml:"description,omitempty"`
+	Type        string       `yaml:"type,omitempty"` // with_data (default) or without_data
+	ActiveSince time.Time    `yaml:"active_since,omitempty"`
+	ActiveTill  time.Time    `yaml:"active_till,omitempty"`
+	Hosts       []string     `yaml:"hosts,omitempty"`  // host names
+	Groups      []string     `yaml:"groups,omitempty"` // host group names
+	TimePeriods []TimePeriod `yaml:"timeperiods,omitempty"`
+	Absent      bool         `yaml:"absent,omitempty"`
+}
+
+// Action is described in action.create format, with IDs of referenced objects.
+// Only given fields are compared with live action.
+type Action struct {
+	Name   string                 `yaml:"name"`
+	Absent bool                   `yaml:"absent,omitempty"`
+	Spec   map[string]interface{} `yaml:",inline"`
+}
+
+var (
+	itemTypes = map[string]zabbix.ItemType{
+		"agent":        zabbix.ZabbixAgent,
+		"snmpv1":       zabbix.SNMPv1Agent,
+		"trapper":      zabbix.ZabbixTrapper,
+		"simple":       zabbix.SimpleCheck,
+		"snmpv2":       zabbix.SNMPv2Agent,
+		"internal":     zabbix.ZabbixInternal,
+		"snmpv3":       zabbix.SNMPv3Agent,
+		"agent_active": zabbix.ZabbixAgentActive,
+		"aggregate":    zabbix.ZabbixAggregate,
+		"web":          zabbix.WebItem,
+		"external":     zabbix.ExternalCheck,
+		"database":     zabbix.DatabaseMonitor,
+		"ipmi":         zabbix.IPMIAgent,
+		"ssh":          zabbix.SSHAgent,
+		"telnet":       zabbix.TELNETAgent,
+		"calculated":   zabbix.Calculated,
+		"jmx":          zabbix.JMXAgent,
+	}
+	valueTypes = map[string]zabbix.ValueType{
+		"float":     zabbix.Float,
+		"character": zabbix.Character,
+		"log":       zabbix.Log,
+		"unsigned":  zabbix.Unsigned,
+		"text":      zabbix.Text,
+	}
+	priorities = map[string]zabbix.PriorityType{
+		"not_classified": zabbix.NotClassified,
+		"information":    zabbix.Information,
+		"warning":        zabbix.Warning,
+		"average":        zabbix.Average,
+		"high":           zabbix.High,
+		"disaster":       zabbix.Disaster,
+	}
+	interfaceTypes = map[string]zabbix.InterfaceType{
+		"agent": zabbix.Agent,
+		"snmp":  zabbix.SNMP,
+		"ipmi":  zabbix.IPMI,
+		"jmx":   zabbix.JMX,
+	}
+	hostStatuses = map[string]zabbix.StatusType{
+		"":            zabbix.Monitored,
+		"monitored":   zabbix.Monitored,
+		"unmonitored": zabbix.Unmonitored,
+	}
+	maintenanceTypes = map[string]zabbix.MaintType{
+		"":             zabbix.WithData,
+		"with_data":    zabbix.WithData,
+		"without_data": zabbix.WithoutData,
+	}
+	periodTypes = map[string]zabbix.PeriodType{
+		"one_time": zabbix.OneTime,
+		"daily":    zabbix.Daily,
+		"weekly":   zabbix.Weekly,
+		"monthly":  zabbix.Monthly,
+	}
+)
+
+// Load reads desired state from YAML and validates it.
+func Load(r io.Reader) (s *State, err error) {
+	s = new(State)
+	d := yaml.NewDecoder(r)
+	d.KnownFields(true)
+	if err = d.Decode(s); err != nil && err != io.EOF {
+		return nil, err
+	}
+	if err = s.Validate(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// LoadFile reads desired state from YAML file and validates it.
+func LoadFile(name string) (s *State, err error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	return Load(f)
+}
+
+// Checks that value is a key of enum map.
+func checkEnum(what, value string, ok bool) error {
+	if !ok {
+		return fmt.Errorf("Unexpected %s %q.", what, value)
+	}
+	return nil
+}
+
+// Validate checks required fields and enumerations.
+func (s *State) Validate() (err error) {
+	for _, g := range s.HostGroups {
+		if g.Name == "" {
+			return fmt.Errorf("Host group without name.")
+		}
+	}
+	for _, t := range s.Templates {
+		if t.Host == "" {
+			return fmt.Errorf("Template without host.")
+		}
+		if !t.Absent && len(t.Groups) == 0 {
+			return fmt.Errorf("Template %q has no groups.", t.Host)
+		}
+	}
+	for _, h := range s.Hosts {
+		if h.Host == "" {
+			return fmt.Errorf("Host without host.")
+		}
+		if h.Absent {
+			continue
+		}
+		if len(h.Groups) == 0 {
+			return fmt.Errorf("Host %q has no groups.", h.Host)
+		}
+		_, ok := hostStatuses[h.Status]
+		if err = checkEnum("host status", h.Status, ok); err != nil {
+			return
+		}
+		for _, i := range h.Interfaces {
+			_, ok := interfaceTypes[i.Type]
+			if err = checkEnum("interface type", i.Type, ok); err != nil {
+				return
+			}
+		}
+	}
+	for _, i := range s.Items {
+		if i.Host == "" || i.Key == "" {
+			return fmt.Errorf("Item without host or key: %#v", i)
+		}
+		if i.Absent {
+			continue
+		}
+		_, ok := itemTypes[i.Type]
+		if err = checkEnum("item type", i.Type, ok || i.Type == ""); err != nil {
+			return
+		}
+		_, ok = valueTypes[i.ValueType]
+		if err = checkEnum("value type", i.ValueType, ok || i.ValueType == ""); err != nil {
+			return
+		}
+	}
+	for _, t := range s.Triggers {
+		if t.Host == "" || t.Description == "" {
+			return fmt.Errorf("Trigger without host or description: %#v", t)
+		}
+		if t.Absent {
+			continue
+		}
+		if t.Expression == "" {
+			return fmt.Errorf("Trigger %q has no expression.", t.Description)
+		}
+		_, ok := priorities[t.Priority]
+		if err = checkEnum("trigger priority", t.Priority, ok); err != nil {
+			return
+		}
+	}
+	for _, m := range s.Maintenances {
+		if m.Name == "" {
+			return fmt.Errorf("Maintenance without name.")
+		}
+		if m.Absent {
+			continue
+		}
+		_, ok := maintenanceTypes[m.Type]
+		if err = checkEnum("maintenance type", m.Type, ok); err != nil {
+			return
+		}
+		for _, p := range m.TimePeriods {
+			_, ok := periodTypes[p.Type]
+			if err = checkEnum("time period type", p.Type, ok); err != nil {
+				return
+			}
+		}
+	}
+	for _, a := range s.Actions {
+		if a.Name == "" {
+			return fmt.Errorf("Action without name.")
+		}
+	}
+	return
+}
@@ -0,0 +1,132 @@
+package zabbix
+
+// RoleRule enables or disables UI element or action of role.
+type RoleRule struct {
+	Name   string `json:"name"`
+	Status int    `json:"status"` // 0 - disabled, 1 - enabled
+}
+
+type RoleModule struct {
+	ModuleId string `json:"moduleid"`
+	Status   int    `json:"status"`
+}
+
+// RoleRules struct from https://www.zabbix.com/documentation/5.2/manual/api/reference/role/object#role_rules
+type RoleRules struct {
+	UI                   []RoleRule   `json:"ui,omitempty"`
+	UIDefaultAccess      int          `json:"ui.default_access"`
+	Modules              []RoleModule `json:"modules,omitempty"`
+	ModulesDefaultAccess int          `json:"modules.default_access"`
+	APIAccess            int          `json:"api.access"`
+	APIMode              int          `json:"api.mode"` // 0 - deny list, 1 - allow list
+	API                  []string     `json:"api,omitempty"`
+	Actions              []RoleRule   `json:"actions,omitempty"`
+	ActionsDefaultAccess int          `json:"actions.default_access"`
+}
+
+// Role struct from https://www.zabbix.com/documentation/5.2/manual/api/reference/role/object (Zabbix 5.2+)
+type Role struct {
+	RoleId   string     `json:"roleid,omitempty"`
+	Name     string     `json:"name,omitempty"`
+	Type     UserType   `json:"type,omitempty"`
+	ReadOnly int        `json:"readonly,omitempty"`
+	Rules    *RoleRules `json:"rules,omitempty"`
+}
+
+type Roles []Role
+
+// RolesGet is a wrapper for role.get: https://www.zabbix.com/documentation/5.2/manual/api/reference/role/get
+// Rules are selected by default.
+func (api *API) RolesGet(params Params) (res Roles, err error) {
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	if _, present := params["selectRules"]; !present {
+		params["selectRules"] = "extend"
+	}
+	response, err := api.CallWithError("role.get", params)
+	if err != nil {
+		return
+	}
+
+	err = decode(response.Result.([]interface{}), &res)
+	return
+}
+
+// RoleGetById gets role by Id only if there is exactly 1 matching role.
+func (api *API) RoleGetById(id string) (res *Role, err error) {
+	roles, err := api.RolesGet(Params{"roleids": id})
+	if err != nil {
+		return
+	}
+
+	if len(roles) == 1 {
+		res = &roles[0]
+	} else {
+		e := ExpectedOneResult(len(roles))
+		err = &e
+	}
+	return
+}
+
+// RolesCreate is a wrapper for role.create: https://www.zabbix.com/documentation/5.2/manual/api/reference/role/create
+func (api *API) RolesCreate(roles Roles) (err error) {
+	response, err := api.CallWithError("role.create", roles)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	roleids := result["roleids"].([]interface{})
+	for i, id := range roleids {
+		roles[i].RoleId = id.(string)
+	}
+	return
+}
+
+// RolesUpdate is a wrapper for role.update: https://www.zabbix.com/documentation/5.2/manual/api/reference/role/update
+func (api *API) RolesUpdate(roles Roles) (err error) {
+	response, err := api.CallWithError("role.update", roles)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	roleids := result["roleids"].([]interface{})
+	if len(roles) != len(roleids) {
+		err = &ExpectedMore{len(roles), len(roleids)}
+	}
+	return
+}
+
+// RolesDelete is a wrapper for role.delete: https://www.zabbix.com/documentation/5.2/manual/api/reference/role/delete
+// Cleans RoleId in all roles elements if call succeed.
+func (api *API) RolesDelete(roles Roles) (err error) {
+	ids := make([]string, len(roles))
+	for i, role := range roles {
+		ids[i] = role.RoleId
+	}
+
+	err = api.RolesDeleteByIds(ids)
+	if err == nil {
+		for i := range roles {
+			roles[i].RoleId = ""
+		}
+	}
+	return
+}
+
+// RolesDeleteByIds is a wrapper for role.delete: https://www.zabbix.com/documentation/5.2/manual/api/reference/role/delete
+func (api *API) RolesDeleteByIds(ids []string) (err error) {
+	response, err := api.CallWithError("role.delete", ids)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	roleids := result["roleids"].([]interface{})
+	if len(ids) != len(roleids) {
+		err = &ExpectedMore{len(ids), len(roleids)}
+	}
+	return
+}
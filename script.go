@@ -0,0 +1,192 @@
+package zabbix
+
+type (
+	ScriptType  int
+	ScriptScope int
+)
+
+const (
+	ScriptCustom  ScriptType = 0
+	ScriptIPMI    ScriptType = 1
+	ScriptSSH     ScriptType = 2 // Zabbix 5.4+
+	ScriptTelnet  ScriptType = 3 // Zabbix 5.4+
+	ScriptWebhook ScriptType = 5 // Zabbix 5.4+
+)
+
+// Scopes of scripts (Zabbix 5.4+), before all scripts are manual host scripts.
+const (
+	ScopeActionOperation ScriptScope = 1
+	ScopeManualHost      ScriptScope = 2
+	ScopeManualEvent     ScriptScope = 4
+)
+
+// ScriptParameter is an input parameter of webhook script.
+type ScriptParameter struct {
+	Name  string `json:"name"`
+	Value string `json:"value,omitempty"`
+}
+
+type ScriptParameters []ScriptParameter
+
+// Script struct from https://www.zabbix.com/documentation/5.4/manual/api/reference/script/object
+type Script struct {
+	ScriptId     string         `json:"scriptid,omitempty"`
+	Name         string         `json:"name,omitempty"`
+	Type         *ScriptType    `json:"type,omitempty"` // required by create
+	Command      string         `json:"command,omitempty"`
+	Scope        ScriptScope    `json:"scope,omitempty"`      // Zabbix 5.4+
+	ExecuteOn    *ExecuteOn     `json:"execute_on,omitempty"` // custom scripts only, nil for server default
+	MenuPath     string         `json:"menu_path,omitempty"`  // Zabbix 5.4+
+	Description  string         `json:"description,omitempty"`
+	Confirmation string         `json:"confirmation,omitempty"`
+	GroupId      string         `json:"groupid,omitempty"`     // host group the script can run on, "0" for all
+	UserGroupId  string         `json:"usrgrpid,omitempty"`    // user group allowed to run the script, "0" for all
+	HostAccess   PermissionType `json:"host_access,omitempty"` // required host permission: read or read-write
+
+	// Fields below are used by Zabbix 5.4+ script types.
+	AuthType   AuthType         `json:"authtype,omitempty"`   // SSH
+	Username   string           `json:"username,omitempty"`   // SSH and Telnet
+	Password   string           `json:"password,omitempty"`   // SSH and Telnet
+	PublicKey  string           `json:"publickey,omitempty"`  // SSH
+	PrivateKey string           `json:"privatekey,omitempty"` // SSH
+	Port       string           `json:"port,omitempty"`       // SSH and Telnet
+	Timeout    string           `json:"timeout,omitempty"`    // webhook, "30s" by default
+	Parameters ScriptParameters `json:"parameters,omitempty"` // webhook
+}
+
+type Scripts []Script
+
+// ScriptResult is a result of script execution.
+type ScriptResult struct {
+	Response string       `json:"response"`        // "success" or "failed"
+	Value    string       `json:"value"`           // output of script
+	Debug    *ScriptDebug `json:"debug,omitempty"` // webhook, Zabbix 5.4+
+}
+
+// ScriptDebug is a log of webhook script execution.
+type ScriptDebug struct {
+	Logs []ScriptLog `json:"logs"`
+	Ms   int         `json:"ms"` // execution time
+}
+
+type ScriptLog struct {
+	Level   int    `json:"level"`
+	Ms      int    `json:"ms"`
+	Message string `json:"message"`
+}
+
+// ScriptsGet is a wrapper for script.get: https://www.zabbix.com/documentation/5.4/manual/api/reference/script/get
+func (api *API) ScriptsGet(params Params) (res Scripts, err error) {
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	response, err := api.CallWithError("script.get", params)
+	if err != nil {
+		return
+	}
+
+	err = decode(response.Result.([]interface{}), &res)
+	return
+}
+
+// ScriptGetById gets script by Id only if there is exactly 1 matching script.
+func (api *API) ScriptGetById(id string) (res *Script, err error) {
+	scripts, err := api.ScriptsGet(Params{"scriptids": id})
+	if err != nil {
+		return
+	}
+
+	if len(scripts) == 1 {
+		res = &scripts[0]
+	} else {
+		e := ExpectedOneResult(len(scripts))
+		err = &e
+	}
+	return
+}
+
+// ScriptsCreate is a wrapper for script.create: https://www.zabbix.com/documentation/5.4/manual/api/reference/script/create
+func (api *API) ScriptsCreate(scripts Scripts) (err error) {
+	response, err := api.CallWithError("script.create", scripts)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	scriptids := result["scriptids"].([]interface{})
+	for i, id := range scriptids {
+		scripts[i].ScriptId = id.(string)
+	}
+	return
+}
+
+// ScriptsUpdate is a wrapper for script.update: https://www.zabbix.com/documentation/5.4/manual/api/reference/script/update
+// Only set fields are sent, so script can be updated partially; Type and ExecuteOn are pointers to allow zero values.
+// Non-empty Parameters replace existing ones.
+func (api *API) ScriptsUpdate(scripts Scripts) (err error) {
+	response, err := api.CallWithError("script.update", scripts)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	scriptids := result["scriptids"].([]interface{})
+	if len(scripts) != len(scriptids) {
+		err = &ExpectedMore{len(scripts), len(scriptids)}
+	}
+	return
+}
+
+// ScriptsDelete is a wrapper for script.delete: https://www.zabbix.com/documentation/5.4/manual/api/reference/script/delete
+// Cleans ScriptId in all scripts elements if call succeed.
+func (api *API) ScriptsDelete(scripts Scripts) (err error) {
+	ids := make([]string, len(scripts))
+	for i, script := range scripts {
+		ids[i] = script.ScriptId
+	}
+
+	err = api.ScriptsDeleteByIds(ids)
+	if err == nil {
+		for i := range scripts {
+			scripts[i].ScriptId = ""
+		}
+	}
+	return
+}
+
+// ScriptsDeleteByIds is a wrapper for script.delete: https://www.zabbix.com/documentation/5.4/manual/api/reference/script/delete
+func (api *API) ScriptsDeleteByIds(ids []string) (err error) {
+	response, err := api.CallWithError("script.delete", ids)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	scriptids := result["scriptids"].([]interface{})
+	if len(ids) != len(scriptids) {
+		err = &ExpectedMore{len(ids), len(scriptids)}
+	}
+	return
+}
+
+// ScriptExecute is a wrapper for script.execute: https://www.zabbix.com/documentation/5.4/manual/api/reference/script/execute
+// Runs manual host script on host. Failed execution is not an error, see Response of result.
+func (api *API) ScriptExecute(scriptId, hostId string) (res *ScriptResult, err error) {
+	return api.scriptExecute(Params{"scriptid": scriptId, "hostid": hostId})
+}
+
+// ScriptExecuteOnEvent runs manual event script on problem event (Zabbix 5.4+).
+func (api *API) ScriptExecuteOnEvent(scriptId, eventId string) (res *ScriptResult, err error) {
+	return api.scriptExecute(Params{"scriptid": scriptId, "eventid": eventId})
+}
+
+func (api *API) scriptExecute(params Params) (res *ScriptResult, err error) {
+	response, err := api.CallWithError("script.execute", params)
+	if err != nil {
+		return
+	}
+
+	res = new(ScriptResult)
+	err = decode(response.Result, res)
+	return
+}
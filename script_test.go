@@ -0,0 +1,65 @@
+package zabbix_test
+
+import (
+	"testing"
+
+	. "."
+)
+
+func TestScripts(t *testing.T) {
+	api, requests := fakeAPI(t, map[string]string{
+		"script.get": `[{"scriptid": "4", "name": "Ping", "type": "5", "scope": "4", "execute_on": "1", "host_access": "2",
+			"timeout": "30s", "parameters": [{"name": "url", "value": "https://example.com"}]}]`,
+		"script.create":  `{"scriptids": ["5"]}`,
+		"script.update":  `{"scriptids": ["4"]}`,
+		"script.execute": `{"response": "success", "value": "PING ok", "debug": {"logs": [{"level": "3", "ms": "12", "message": "sent"}], "ms": "15"}}`,
+	})
+
+	s, err := api.ScriptGetById("4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Type == nil || *s.Type != ScriptWebhook || s.Scope != ScopeManualEvent || s.ExecuteOn == nil || *s.ExecuteOn != ZABBIX_SERVER || s.HostAccess != PermissionRead || s.Parameters[0].Name != "url" {
+		t.Errorf("Unexpected script %#v", s)
+	}
+
+	ssh, custom := ScriptSSH, ScriptCustom
+	scripts := Scripts{{Name: "Restart", Type: &ssh, Scope: ScopeActionOperation, Command: "systemctl restart app", Username: "zabbix", AuthType: PUBLIC_KEY}}
+	if err = api.ScriptsCreate(scripts); err != nil || scripts[0].ScriptId != "5" {
+		t.Fatalf("Unexpected script %#v: %v", scripts[0], err)
+	}
+	params := (*requests)[1]["params"].([]interface{})[0].(map[string]interface{})
+	if params["type"] != 2.0 || params["scope"] != 1.0 || params["authtype"] != 1.0 || params["parameters"] != nil {
+		t.Errorf("Unexpected params %v", params)
+	}
+
+	agent := ZABBIX_AGENT
+	scripts = Scripts{{Name: "Uptime", Type: &custom, Command: "uptime", ExecuteOn: &agent}}
+	if err = api.ScriptsCreate(scripts); err != nil {
+		t.Fatal(err)
+	}
+	params = (*requests)[2]["params"].([]interface{})[0].(map[string]interface{})
+	if on, present := params["execute_on"]; !present || on != 0.0 || params["type"] != 0.0 {
+		t.Errorf("Unexpected params %v", params)
+	}
+
+	if err = api.ScriptsUpdate(Scripts{{ScriptId: "4", Name: "Ping"}}); err != nil {
+		t.Fatal(err)
+	}
+	params = (*requests)[3]["params"].([]interface{})[0].(map[string]interface{})
+	if len(params) != 2 || params["name"] != "Ping" {
+		t.Errorf("Unexpected params %v", params)
+	}
+
+	res, err := api.ScriptExecute("4", "10084")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Response != "success" || res.Value != "PING ok" || res.Debug.Ms != 15 || res.Debug.Logs[0].Message != "sent" {
+		t.Errorf("Unexpected result %#v", res)
+	}
+	params = (*requests)[4]["params"].(map[string]interface{})
+	if params["scriptid"] != "4" || params["hostid"] != "10084" {
+		t.Errorf("Unexpected params %v", params)
+	}
+}
@@ -0,0 +1,166 @@
+package zabbix
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// SenderValue is a value for ZabbixTrapper item sent with Sender.
+// Clock and Ns are optional, server uses time of receiving if Clock is zero.
+type SenderValue struct {
+	Host  string `json:"host"`
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	Clock int64  `json:"clock,omitempty"`
+	Ns    int    `json:"ns,omitempty"`
+}
+
+type SenderValues []SenderValue
+
+// SenderResponse is server (or proxy) response to sent values, summed for all batches.
+type SenderResponse struct {
+	Response  string  `json:"response"`
+	Info      string  `json:"info"`
+	Processed int     `json:"-"`
+	Failed    int     `json:"-"`
+	Total     int     `json:"-"`
+	Spent     float64 `json:"-"`
+}
+
+var senderInfoRE = regexp.MustCompile(`processed:? (\d+); failed:? (\d+); total:? (\d+); seconds spent:? ([0-9.]+)`)
+
+// Parses "processed: 1; failed: 0; total: 1; seconds spent: 0.000055" from Info.
+func (r *SenderResponse) parseInfo() (err error) {
+	m := senderInfoRE.FindStringSubmatch(r.Info)
+	if m == nil {
+		return ProtocolError(fmt.Sprintf("unexpected sender response info %q", r.Info))
+	}
+	r.Processed, _ = strconv.Atoi(m[1])
+	r.Failed, _ = strconv.Atoi(m[2])
+	r.Total, _ = strconv.Atoi(m[3])
+	r.Spent, _ = strconv.ParseFloat(m[4], 64)
+	return
+}
+
+// Sender sends values to Zabbix server or proxy trapper like zabbix_sender does.
+// See https://www.zabbix.com/documentation/4.0/manual/appendix/items/trapper
+type Sender struct {
+	Addr      string        // server or proxy address, host:port
+	Timeout   time.Duration // connection and I/O timeout for each batch
+	BatchSize int           // maximum number of values sent in one request
+	Compress  bool          // compress requests (Zabbix 4.0+)
+
+	// TLSConfig enables TLS with certificates if not nil.
+	// crypto/tls doesn't support PSK: use Dial with third-party TLS implementation for it.
+	TLSConfig *tls.Config
+
+	// Dial is used to open connections if not nil.
+	Dial func(network, addr string) (net.Conn, error)
+}
+
+// NewSender creates new Sender with zabbix_sender defaults.
+// Default port 10051 is used if addr doesn't contain one.
+func NewSender(addr string) *Sender {
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, "10051")
+	}
+	return &Sender{Addr: addr, Timeout: 30 * time.Second, BatchSize: 250}
+}
+
+type senderRequest struct {
+	Request string       `json:"request"`
+	Data    SenderValues `json:"data"`
+	Clock   int64        `json:"clock,omitempty"`
+	Ns      int          `json:"ns,omitempty"`
+}
+
+func (s *Sender) dial() (conn net.Conn, err error) {
+	switch {
+	case s.Dial != nil:
+		conn, err = s.Dial("tcp", s.Addr)
+	case s.TLSConfig != nil:
+		conn, err = tls.DialWithDialer(&net.Dialer{Timeout: s.Timeout}, "tcp", s.Addr, s.TLSConfig)
+	default:
+		conn, err = net.DialTimeout("tcp", s.Addr, s.Timeout)
+	}
+	if err == nil && s.Timeout > 0 {
+		conn.SetDeadline(time.Now().Add(s.Timeout))
+	}
+	return
+}
+
+// Sends one batch.
+func (s *Sender) send(values SenderValues) (res SenderResponse, err error) {
+	req := senderRequest{Request: "sender data", Data: values}
+	for _, v := range values {
+		// request time allows server to adjust value timestamps for clock difference
+		if v.Clock != 0 {
+			now := time.Now()
+			req.Clock, req.Ns = now.Unix(), now.Nanosecond()
+			break
+		}
+	}
+	b, err := json.Marshal(req)
+	if err != nil {
+		return
+	}
+
+	conn, err := s.dial()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	if err = WritePacket(conn, b, s.Compress); err != nil {
+		return
+	}
+	b, err = ReadPacket(conn)
+	if err != nil {
+		return
+	}
+
+	if err = json.Unmarshal(b, &res); err != nil {
+		return
+	}
+	if res.Response != "success" {
+		err = ProtocolError(fmt.Sprintf("sender response %q: %s", res.Response, res.Info))
+		return
+	}
+	err = res.parseInfo()
+	return
+}
+
+// Send sends values in batches of BatchSize.
+// It stops on first failed batch, response contains sums for batches sent before it.
+// Values rejected by server (for example, for non-existing items) are counted in Failed field and are not an error.
+func (s *Sender) Send(values SenderValues) (res SenderResponse, err error) {
+	size := s.BatchSize
+	if size <= 0 {
+		size = len(values)
+	}
+
+	res.Response = "success"
+	for start := 0; start < len(values); start += size {
+		end := start + size
+		if end > len(values) {
+			end = len(values)
+		}
+
+		var r SenderResponse
+		r, err = s.send(values[start:end])
+		if err != nil {
+			return
+		}
+		res.Info = r.Info
+		res.Processed += r.Processed
+		res.Failed += r.Failed
+		res.Total += r.Total
+		res.Spent += r.Spent
+	}
+	return
+}
@@ -0,0 +1,91 @@
+package zabbix_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"testing"
+
+	. "."
+)
+
+// Starts trapper stand-in which accepts values with keys not starting with "bad".
+func startTrapper(t *testing.T) (addr string, requests chan map[string]interface{}) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	requests = make(chan map[string]interface{}, 10)
+
+	go func() {
+		defer l.Close()
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			b, err := ReadPacket(conn)
+			if err != nil {
+				t.Error(err)
+				conn.Close()
+				continue
+			}
+
+			var req map[string]interface{}
+			json.Unmarshal(b, &req)
+			requests <- req
+
+			var processed, failed int
+			for _, v := range req["data"].([]interface{}) {
+				if key := v.(map[string]interface{})["key"].(string); len(key) >= 3 && key[:3] == "bad" {
+					failed++
+				} else {
+					processed++
+				}
+			}
+			info := fmt.Sprintf("processed: %d; failed: %d; total: %d; seconds spent: 0.000100", processed, failed, processed+failed)
+			b, _ = json.Marshal(map[string]string{"response": "success", "info": info})
+			WritePacket(conn, b, false)
+			conn.Close()
+		}
+	}()
+	return l.Addr().String(), requests
+}
+
+func TestSender(t *testing.T) {
+	addr, requests := startTrapper(t)
+
+	sender := NewSender(addr)
+	sender.BatchSize = 2
+	sender.Compress = true
+	values := SenderValues{
+		{Host: "host", Key: "key1", Value: "1"},
+		{Host: "host", Key: "bad.key", Value: "2"},
+		{Host: "host", Key: "key3", Value: "3", Clock: 1500000000, Ns: 42},
+	}
+
+	res, err := sender.Send(values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Processed != 2 || res.Failed != 1 || res.Total != 3 || res.Spent != 0.0002 {
+		t.Errorf("Bad response: %#v", res)
+	}
+
+	req := <-requests
+	if req["request"] != "sender data" || len(req["data"].([]interface{})) != 2 || req["clock"] != nil {
+		t.Errorf("Bad first request: %#v", req)
+	}
+	req = <-requests
+	data := req["data"].([]interface{})
+	if len(data) != 1 || data[0].(map[string]interface{})["clock"] != 1500000000.0 || req["clock"] == nil {
+		t.Errorf("Bad second request: %#v", req)
+	}
+}
+
+func TestSenderDefaultPort(t *testing.T) {
+	sender := NewSender("zabbix.example.com")
+	if sender.Addr != "zabbix.example.com:10051" {
+		t.Errorf("Bad address: %s", sender.Addr)
+	}
+}
@@ -1,13 +1,22 @@
 package zabbix
 
-import "github.com/mitchellh/mapstructure"
-
 // https://www.zabbix.com/documentation/2.2/manual/api/reference/template/object
 type Template struct {
 	TemplateId  string `json:"templateid,omitempty"`
 	Host        string `json:"host"`
 	Description string `json:"description,omitempty"`
 	Name        string `json:"name,omitempty"`
+
+	// Fields below used only when creating and updating templates
+	GroupIds    HostGroupIds `json:"groups,omitempty"`
+	TemplateIds TemplateIds  `json:"templates,omitempty"` // templates to link
+	HostIds     HostIds      `json:"hosts,omitempty"`     // hosts and templates to link to
+
+	// Fields below filled by TemplatesGet with selectHosts, selectItems, selectTriggers and selectParentTemplates
+	Hosts           Hosts     `json:"-"`
+	Items           Items     `json:"-"`
+	Triggers        Triggers  `json:"-"`
+	ParentTemplates Templates `json:"-"`
 }
 
 type Templates []Template
@@ -18,6 +27,22 @@ type TemplateId struct {
 
 type TemplateIds []TemplateId
 
+func toTemplateIds(ids []string) (res TemplateIds) {
+	res = make(TemplateIds, len(ids))
+	for i, id := range ids {
+		res[i].TemplateId = id
+	}
+	return
+}
+
+func toHostIds(ids []string) (res HostIds) {
+	res = make(HostIds, len(ids))
+	for i, id := range ids {
+		res[i].HostId = id
+	}
+	return
+}
+
 // Wrapper for template.get: https://www.zabbix.com/documentation/2.2/manual/api/reference/template/get
 func (api *API) TemplatesGet(params Params) (res Templates, err error) {
 	if _, present := params["output"]; !present {
@@ -28,7 +53,176 @@ func (api *API) TemplatesGet(params Params) (res Templates, err error) {
 		return
 	}
 
-	mapstructure.Decode(response.Result.([]interface{}), &res)
+	res = make(Templates, len(response.Result.([]interface{})))
+	for i, h := range response.Result.([]interface{}) {
+		h2 := h.(map[string]interface{})
+		if err = decode(h2, &res[i]); err != nil {
+			return
+		}
+
+		// selected objects are not decoded above, see json tags of Template fields
+		for name, v := range map[string]interface{}{
+			"hosts":           &res[i].Hosts,
+			"items":           &res[i].Items,
+			"triggers":        &res[i].Triggers,
+			"parentTemplates": &res[i].ParentTemplates,
+		} {
+			if objects, ok := h2[name].([]interface{}); ok {
+				if err = decode(objects, v); err != nil {
+					return
+				}
+			}
+		}
+	}
+
+	return
+}
+
+// Gets template by Id only if there is exactly 1 matching template.
+func (api *API) TemplateGetById(id string) (res *Template, err error) {
+	templates, err := api.TemplatesGet(Params{"templateids": id})
+	if err != nil {
+		return
+	}
+
+	if len(templates) == 1 {
+		res = &templates[0]
+	} else {
+		e := ExpectedOneResult(len(templates))
+		err = &e
+	}
+	return
+}
+
+// Wrapper for template.create: https://www.zabbix.com/documentation/2.2/manual/api/reference/template/create
+func (api *API) TemplatesCreate(templates Templates) (err error) {
+	response, err := api.CallWithError("template.create", templates)
+	if err != nil {
+		return
+	}
 
+	result := response.Result.(map[string]interface{})
+	templateids := result["templateids"].([]interface{})
+	for i, id := range templateids {
+		templates[i].TemplateId = id.(string)
+	}
 	return
 }
+
+// Wrapper for template.update: https://www.zabbix.com/documentation/2.2/manual/api/reference/template/update
+// Non-empty TemplateIds replace linked templates, so ones missing from them are unlinked without clearing;
+// use TemplatesLinkTemplates to link templates keeping existing links.
+func (api *API) TemplatesUpdate(templates Templates) (err error) {
+	response, err := api.CallWithError("template.update", templates)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	templateids := result["templateids"].([]interface{})
+	if len(templates) != len(templateids) {
+		err = &ExpectedMore{len(templates), len(templateids)}
+	}
+	return
+}
+
+// Wrapper for template.delete: https://www.zabbix.com/documentation/2.2/manual/api/reference/template/delete
+// Cleans TemplateId in all templates elements if call succeed.
+func (api *API) TemplatesDelete(templates Templates) (err error) {
+	ids := make([]string, len(templates))
+	for i, template := range templates {
+		ids[i] = template.TemplateId
+	}
+
+	err = api.TemplatesDeleteByIds(ids)
+	if err == nil {
+		for i := range templates {
+			templates[i].TemplateId = ""
+		}
+	}
+	return
+}
+
+// Wrapper for template.delete: https://www.zabbix.com/documentation/2.2/manual/api/reference/template/delete
+func (api *API) TemplatesDeleteByIds(ids []string) (err error) {
+	response, err := api.CallWithError("template.delete", ids)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	templateids := result["templateids"].([]interface{})
+	if len(ids) != len(templateids) {
+		err = &ExpectedMore{len(ids), len(templateids)}
+	}
+	return
+}
+
+// Parameters for template.massadd and template.massupdate.
+// Empty fields are omitted, so TemplatesMassUpdate can't be used to remove all links of some kind.
+type TemplateMass struct {
+	Templates      TemplateIds  `json:"templates"`
+	Groups         HostGroupIds `json:"groups,omitempty"`
+	Hosts          HostIds      `json:"hosts,omitempty"`           // hosts and templates to link to
+	TemplatesLink  TemplateIds  `json:"templates_link,omitempty"`  // templates to link
+	TemplatesClear TemplateIds  `json:"templates_clear,omitempty"` // templates to unlink and clear, massupdate only
+}
+
+// Parameters for template.massremove.
+type TemplateMassRemove struct {
+	TemplateIds      []string `json:"templateids"`
+	GroupIds         []string `json:"groupids,omitempty"`
+	HostIds          []string `json:"hostids,omitempty"`           // hosts and templates to unlink from
+	TemplateIdsLink  []string `json:"templateids_link,omitempty"`  // templates to unlink
+	TemplateIdsClear []string `json:"templateids_clear,omitempty"` // templates to unlink and clear
+}
+
+// Wrapper for template.massadd: https://www.zabbix.com/documentation/2.2/manual/api/reference/template/massadd
+func (api *API) TemplatesMassAdd(params TemplateMass) (err error) {
+	_, err = api.CallWithError("template.massadd", params)
+	return
+}
+
+// Wrapper for template.massupdate: https://www.zabbix.com/documentation/2.2/manual/api/reference/template/massupdate
+func (api *API) TemplatesMassUpdate(params TemplateMass) (err error) {
+	_, err = api.CallWithError("template.massupdate", params)
+	return
+}
+
+// Wrapper for template.massremove: https://www.zabbix.com/documentation/2.2/manual/api/reference/template/massremove
+func (api *API) TemplatesMassRemove(params TemplateMassRemove) (err error) {
+	_, err = api.CallWithError("template.massremove", params)
+	return
+}
+
+// Links templates to hosts (or other templates).
+func (api *API) TemplatesLinkHosts(templateIds, hostIds []string) (err error) {
+	return api.TemplatesMassAdd(TemplateMass{Templates: toTemplateIds(templateIds), Hosts: toHostIds(hostIds)})
+}
+
+// Unlinks templates from hosts (or other templates).
+// If clear is true, items, triggers and other entities inherited from templates are removed too.
+func (api *API) TemplatesUnlinkHosts(templateIds, hostIds []string, clear bool) (err error) {
+	if clear {
+		// template.massremove can't clear hosts
+		_, err = api.CallWithError("host.massremove", Params{"hostids": hostIds, "templateids_clear": templateIds})
+		return
+	}
+	return api.TemplatesMassRemove(TemplateMassRemove{TemplateIds: templateIds, HostIds: hostIds})
+}
+
+// Links nested templates to templates.
+func (api *API) TemplatesLinkTemplates(templateIds, linkIds []string) (err error) {
+	return api.TemplatesMassAdd(TemplateMass{Templates: toTemplateIds(templateIds), TemplatesLink: toTemplateIds(linkIds)})
+}
+
+// Unlinks nested templates from templates, clearing inherited entities if clear is true.
+func (api *API) TemplatesUnlinkTemplates(templateIds, linkIds []string, clear bool) (err error) {
+	params := TemplateMassRemove{TemplateIds: templateIds}
+	if clear {
+		params.TemplateIdsClear = linkIds
+	} else {
+		params.TemplateIdsLink = linkIds
+	}
+	return api.TemplatesMassRemove(params)
+}
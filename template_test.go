@@ -1,11 +1,34 @@
 package zabbix_test
 
 import (
+	"fmt"
+	"math/rand"
 	"testing"
 
 	. "."
 )
 
+func CreateTemplate(hostGroup *HostGroup, t *testing.T) *Template {
+	name := fmt.Sprintf("Template %s-%d", getHost(), rand.Int())
+	templates := Templates{{
+		Host:     name,
+		Name:     "Name for " + name,
+		GroupIds: HostGroupIds{{hostGroup.GroupId}},
+	}}
+	err := getAPI(t).TemplatesCreate(templates)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &templates[0]
+}
+
+func DeleteTemplate(template *Template, t *testing.T) {
+	err := getAPI(t).TemplatesDelete(Templates{*template})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
 func TestTemplates(t *testing.T) {
 	api := getAPI(t)
 
@@ -17,4 +40,96 @@ func TestTemplates(t *testing.T) {
 	if len(templates) == 0 {
 		t.Fatal("No templates were obtained")
 	}
+
+	group := CreateHostGroup(t)
+	defer DeleteHostGroup(group, t)
+
+	host := CreateHost(group, t)
+	defer DeleteHost(host, t)
+
+	template := CreateTemplate(group, t)
+	if template.TemplateId == "" {
+		t.Errorf("Id is empty: %#v", template)
+	}
+	parent := CreateTemplate(group, t)
+
+	template.Description = "Updated"
+	err = api.TemplatesUpdate(Templates{{TemplateId: template.TemplateId, Host: template.Host, Description: template.Description}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = api.TemplatesLinkHosts([]string{template.TemplateId}, []string{host.HostId})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = api.TemplatesLinkTemplates([]string{template.TemplateId}, []string{parent.TemplateId})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	templates, err = api.TemplatesGet(Params{
+		"templateids":           template.TemplateId,
+		"selectHosts":           "extend",
+		"selectParentTemplates": "extend",
+		"selectItems":           "extend",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(templates) != 1 || templates[0].Description != "Updated" {
+		t.Fatalf("Bad templates: %#v", templates)
+	}
+	if len(templates[0].Hosts) != 1 || templates[0].Hosts[0].HostId != host.HostId {
+		t.Errorf("Bad hosts: %#v", templates[0].Hosts)
+	}
+	if len(templates[0].ParentTemplates) != 1 || templates[0].ParentTemplates[0].TemplateId != parent.TemplateId {
+		t.Errorf("Bad parent templates: %#v", templates[0].ParentTemplates)
+	}
+	if len(templates[0].Items) != 0 {
+		t.Errorf("Bad items: %#v", templates[0].Items)
+	}
+
+	err = api.TemplatesUnlinkHosts([]string{template.TemplateId}, []string{host.HostId}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = api.TemplatesUnlinkTemplates([]string{template.TemplateId}, []string{parent.TemplateId}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template2, err := api.TemplateGetById(template.TemplateId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if template2.Host != template.Host {
+		t.Errorf("Templates are not equal:\n%#v\n%#v", template, template2)
+	}
+
+	DeleteTemplate(template, t)
+	DeleteTemplate(parent, t)
+}
+
+func TestTemplatesGetSelected(t *testing.T) {
+	api, _ := fakeAPI(t, map[string]string{
+		"template.get": `[{"templateid": "10001", "host": "Template OS Linux", "hosts": [{"hostid": "10084", "host": "web-1", "status": "0"}],
+			"items": [{"itemid": "23", "key_": "agent.ping", "value_type": "3"}], "triggers": [{"triggerid": "13", "description": "Down", "priority": "4"}],
+			"parentTemplates": [{"templateid": "10002", "host": "Template Module ICMP"}]}]`,
+	})
+	templates, err := api.TemplatesGet(Params{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tpl := templates[0]
+	if tpl.Hosts[0].HostId != "10084" || tpl.Items[0].ValueType != Unsigned || tpl.Triggers[0].Priority != High || tpl.ParentTemplates[0].Host != "Template Module ICMP" {
+		t.Errorf("Unexpected template %#v", tpl)
+	}
+
+	api, _ = fakeAPI(t, map[string]string{
+		"template.get": `[{"templateid": "10001", "host": "Template OS Linux", "hosts": [{"hostid": "10084", "status": "broken"}]}]`,
+	})
+	if _, err = api.TemplatesGet(Params{}); err == nil {
+		t.Error("Expected decode error")
+	}
 }
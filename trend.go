@@ -0,0 +1,107 @@
+package zabbix
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// https://www.zabbix.com/documentation/3.0/manual/api/reference/trend/object
+type Trend struct {
+	ItemId   string  `json:"itemid"`
+	Clock    uint    `json:"clock"`
+	Num      int     `json:"num"`
+	ValueMin float64 `json:"value_min"`
+	ValueAvg float64 `json:"value_avg"`
+	ValueMax float64 `json:"value_max"`
+}
+
+type Trends []Trend
+
+// Wrapper for trend.get: https://www.zabbix.com/documentation/3.0/manual/api/reference/trend/get
+func (api *API) TrendsGet(params Params) (res Trends, err error) {
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	response, err := api.CallWithError("trend.get", params)
+	if err != nil {
+		return
+	}
+
+	err = decode(response.Result.([]interface{}), &res)
+	return
+}
+
+// SeriesPoint is aggregated value of Num samples starting at Clock.
+type SeriesPoint struct {
+	Clock uint
+	Num   int
+	Min   float64
+	Avg   float64
+	Max   float64
+}
+
+type Series []SeriesPoint
+
+func (p *SeriesPoint) add(num int, min, avg, max float64) {
+	if p.Num == 0 || min < p.Min {
+		p.Min = min
+	}
+	if p.Num == 0 || max > p.Max {
+		p.Max = max
+	}
+	p.Avg = (p.Avg*float64(p.Num) + avg*float64(num)) / float64(p.Num+num)
+	p.Num += num
+}
+
+// MergeSeries merges trends and recent history of the same numeric item into one time series
+// with a point per resolution (for example, 5*time.Minute), sorted by Clock.
+// History takes precedence: trends covering time after the oldest history value are skipped.
+// As trends are hourly, resolution below one hour puts each trend into the bucket of its Clock.
+func MergeSeries(trends Trends, histories Histories, resolution time.Duration) (res Series, err error) {
+	step := uint(resolution / time.Second)
+	if step == 0 {
+		err = fmt.Errorf("Resolution should be at least one second, got %s.", resolution)
+		return
+	}
+
+	var historyFrom uint
+	for i, h := range histories {
+		if i == 0 || h.Clock < historyFrom {
+			historyFrom = h.Clock
+		}
+	}
+
+	buckets := make(map[uint]*SeriesPoint)
+	bucket := func(clock uint) *SeriesPoint {
+		clock -= clock % step
+		p, ok := buckets[clock]
+		if !ok {
+			p = &SeriesPoint{Clock: clock}
+			buckets[clock] = p
+		}
+		return p
+	}
+
+	for _, t := range trends {
+		if t.Num <= 0 || (len(histories) > 0 && t.Clock+3600 > historyFrom) {
+			continue
+		}
+		bucket(t.Clock).add(t.Num, t.ValueMin, t.ValueAvg, t.ValueMax)
+	}
+	for _, h := range histories {
+		var v float64
+		v, err = h.Float()
+		if err != nil {
+			return
+		}
+		bucket(h.Clock).add(1, v, v, v)
+	}
+
+	res = make(Series, 0, len(buckets))
+	for _, p := range buckets {
+		res = append(res, *p)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Clock < res[j].Clock })
+	return
+}
@@ -0,0 +1,76 @@
+package zabbix_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	. "."
+)
+
+func TestTrends(t *testing.T) {
+	api := getAPI(t)
+
+	group := CreateHostGroup(t)
+	defer DeleteHostGroup(group, t)
+
+	host := CreateHost(group, t)
+	defer DeleteHost(host, t)
+
+	app := CreateApplication(host, t)
+	defer DeleteApplication(app, t)
+
+	item := CreateItem(app, t)
+	defer DeleteItem(item, t)
+
+	trends, err := api.TrendsGet(Params{"itemids": item.ItemId})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trends) != 0 {
+		t.Errorf("Bad trends: %#v", trends)
+	}
+}
+
+func TestMergeSeries(t *testing.T) {
+	trends := Trends{
+		{Clock: 0, Num: 2, ValueMin: 1, ValueAvg: 2, ValueMax: 3},
+		{Clock: 3600, Num: 2, ValueMin: 0, ValueAvg: 4, ValueMax: 5},
+		{Clock: 7200, Num: 60, ValueMin: 0, ValueAvg: 1, ValueMax: 100}, // overlaps history
+	}
+	histories := Histories{
+		{Clock: 7500, Value: "3"},
+		{Clock: 7300, Value: "1"},
+		{Clock: 7900, Value: "10"},
+	}
+
+	series, err := MergeSeries(trends, histories, 2*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := Series{
+		{Clock: 0, Num: 4, Min: 0, Avg: 3, Max: 5},
+		{Clock: 7200, Num: 3, Min: 1, Avg: 14.0 / 3, Max: 10},
+	}
+	if !reflect.DeepEqual(expected, series) {
+		t.Errorf("Series are not equal:\n%#v\n%#v", expected, series)
+	}
+
+	series, err = MergeSeries(nil, histories, 5*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = Series{
+		{Clock: 7200, Num: 1, Min: 1, Avg: 1, Max: 1},
+		{Clock: 7500, Num: 1, Min: 3, Avg: 3, Max: 3},
+		{Clock: 7800, Num: 1, Min: 10, Avg: 10, Max: 10},
+	}
+	if !reflect.DeepEqual(expected, series) {
+		t.Errorf("Series are not equal:\n%#v\n%#v", expected, series)
+	}
+
+	_, err = MergeSeries(trends, nil, time.Millisecond)
+	if err == nil {
+		t.Error("Expected error for bad resolution")
+	}
+}
@@ -1,7 +1,5 @@
 package zabbix
 
-import "github.com/mitchellh/mapstructure"
-
 type (
 	PriorityType int
 )
@@ -20,14 +18,15 @@ const (
 	TriggerProblem ValueType = 1
 )
 
+// https://www.zabbix.com/documentation/2.2/manual/api/reference/trigger/object
 type Trigger struct {
-	TriggerId   string       `json:"triggerid"`
+	TriggerId   string       `json:"triggerid,omitempty"`
 	Description string       `json:"description"`
 	Expression  string       `json:"expression"`
-	Error       string       `json:"error"`
+	Error       string       `json:"error,omitempty"`
 	Hosts       Hosts        `json:"hosts,omitempty"`
 	Priority    PriorityType `json:"priority"`
-	Value       ValueType    `json:"value"`
+	Value       ValueType    `json:"value,omitempty"`
 }
 
 type Triggers []Trigger
@@ -41,17 +40,7 @@ func (api *API) TriggersGet(params Params) (res Triggers, err error) {
 	if err != nil {
 		return
 	}
-	res = make(Triggers, len(response.Result.([]interface{})))
-	for i, h := range response.Result.([]interface{}) {
-		h2 := h.(map[string]interface{})
-		mapstructure.Decode(h2, &res[i])
-
-		if hosts, ok := h2["hosts"]; ok {
-			mapstructure.Decode(hosts.([]interface{}), &res[i].Hosts)
-
-		}
-	}
-
+	err = decode(response.Result.([]interface{}), &res)
 	return
 }
 
@@ -70,3 +59,65 @@ func (api *API) TriggerGetByID(id string) (res *Trigger, err error) {
 	}
 	return
 }
+
+// Wrapper for trigger.create: https://www.zabbix.com/documentation/2.2/manual/api/reference/trigger/create
+func (api *API) TriggersCreate(triggers Triggers) (err error) {
+	response, err := api.CallWithError("trigger.create", triggers)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	triggerids := result["triggerids"].([]interface{})
+	for i, id := range triggerids {
+		triggers[i].TriggerId = id.(string)
+	}
+	return
+}
+
+// Wrapper for trigger.update: https://www.zabbix.com/documentation/2.2/manual/api/reference/trigger/update
+func (api *API) TriggersUpdate(triggers Triggers) (err error) {
+	response, err := api.CallWithError("trigger.update", triggers)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	triggerids := result["triggerids"].([]interface{})
+	if len(triggers) != len(triggerids) {
+		err = &ExpectedMore{len(triggers), len(triggerids)}
+	}
+	return
+}
+
+// Wrapper for trigger.delete: https://www.zabbix.com/documentation/2.2/manual/api/reference/trigger/delete
+// Cleans TriggerId in all triggers elements if call succeed.
+func (api *API) TriggersDelete(triggers Triggers) (err error) {
+	ids := make([]string, len(triggers))
+	for i, trigger := range triggers {
+		ids[i] = trigger.TriggerId
+	}
+
+	err = api.TriggersDeleteByIds(ids)
+	if err == nil {
+		for i := range triggers {
+			triggers[i].TriggerId = ""
+		}
+	}
+	return
+}
+
+// Wrapper for trigger.delete: https://www.zabbix.com/documentation/2.2/manual/api/reference/trigger/delete
+func (api *API) TriggersDeleteByIds(ids []string) (err error) {
+	response, err := api.CallWithError("trigger.delete", ids)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	triggerids := result["triggerids"].([]interface{})
+	if len(ids) != len(triggerids) {
+		err = &ExpectedMore{len(ids), len(triggerids)}
+	}
+	return
+}
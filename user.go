@@ -0,0 +1,184 @@
+package zabbix
+
+import "fmt"
+
+type UserType int
+
+// Types of users before Zabbix 5.2, and types of roles since.
+const (
+	UserTypeUser       UserType = 1
+	UserTypeAdmin      UserType = 2
+	UserTypeSuperAdmin UserType = 3
+)
+
+// User struct from https://www.zabbix.com/documentation/4.0/manual/api/reference/user/object
+type User struct {
+	UserId      string       `json:"userid,omitempty"`
+	Alias       string       `json:"alias,omitempty"`    // before Zabbix 5.4
+	Username    string       `json:"username,omitempty"` // Zabbix 5.4+
+	Name        string       `json:"name,omitempty"`
+	Surname     string       `json:"surname,omitempty"`
+	Password    string       `json:"passwd,omitempty"`    // only for create and update
+	Type        UserType     `json:"type,omitempty"`      // before Zabbix 5.2
+	RoleId      string       `json:"roleid,omitempty"`    // Zabbix 5.2+
+	AutoLogin   *int         `json:"autologin,omitempty"` // 0 - disabled, 1 - enabled
+	AutoLogout  string       `json:"autologout,omitempty"`
+	Lang        string       `json:"lang,omitempty"`
+	Refresh     string       `json:"refresh,omitempty"`
+	RowsPerPage int          `json:"rows_per_page,omitempty"`
+	Theme       string       `json:"theme,omitempty"`
+	URL         string       `json:"url,omitempty"`
+	UserGroups  UserGroupIds `json:"usrgrps,omitempty"`
+	Medias      Medias       `json:"medias,omitempty"` // Zabbix 5.2+, see UserMediasUpdate
+}
+
+type Users []User
+
+// UsersGet is a wrapper for user.get: https://www.zabbix.com/documentation/4.0/manual/api/reference/user/get
+func (api *API) UsersGet(params Params) (res Users, err error) {
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	response, err := api.CallWithError("user.get", params)
+	if err != nil {
+		return
+	}
+
+	err = decode(response.Result.([]interface{}), &res)
+	return
+}
+
+// UserGetById gets user with user group Ids by Id only if there is exactly 1 matching user.
+func (api *API) UserGetById(id string) (res *User, err error) {
+	users, err := api.UsersGet(Params{"userids": id, "selectUsrgrps": []string{"usrgrpid"}})
+	if err != nil {
+		return
+	}
+
+	if len(users) == 1 {
+		res = &users[0]
+	} else {
+		e := ExpectedOneResult(len(users))
+		err = &e
+	}
+	return
+}
+
+// UsersCreate is a wrapper for user.create: https://www.zabbix.com/documentation/4.0/manual/api/reference/user/create
+func (api *API) UsersCreate(users Users) (err error) {
+	response, err := api.CallWithError("user.create", users)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	userids := result["userids"].([]interface{})
+	for i, id := range userids {
+		users[i].UserId = id.(string)
+	}
+	return
+}
+
+// UsersUpdate is a wrapper for user.update: https://www.zabbix.com/documentation/4.0/manual/api/reference/user/update
+// Non-empty UserGroups replace existing ones.
+func (api *API) UsersUpdate(users Users) (err error) {
+	response, err := api.CallWithError("user.update", users)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	userids := result["userids"].([]interface{})
+	if len(users) != len(userids) {
+		err = &ExpectedMore{len(users), len(userids)}
+	}
+	return
+}
+
+// UsersDelete is a wrapper for user.delete: https://www.zabbix.com/documentation/4.0/manual/api/reference/user/delete
+// Cleans UserId in all users elements if call succeed.
+func (api *API) UsersDelete(users Users) (err error) {
+	ids := make([]string, len(users))
+	for i, user := range users {
+		ids[i] = user.UserId
+	}
+
+	err = api.UsersDeleteByIds(ids)
+	if err == nil {
+		for i := range users {
+			users[i].UserId = ""
+		}
+	}
+	return
+}
+
+// UsersDeleteByIds is a wrapper for user.delete: https://www.zabbix.com/documentation/4.0/manual/api/reference/user/delete
+func (api *API) UsersDeleteByIds(ids []string) (err error) {
+	response, err := api.CallWithError("user.delete", ids)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	userids := result["userids"].([]interface{})
+	if len(ids) != len(userids) {
+		err = &ExpectedMore{len(ids), len(userids)}
+	}
+	return
+}
+
+// Media struct from https://www.zabbix.com/documentation/4.0/manual/api/reference/user/object#media
+type Media struct {
+	MediaId     string `json:"mediaid,omitempty"`
+	MediaTypeId string `json:"mediatypeid"`
+
+	// Address, user name or other identifier: string, or array of strings for email media types.
+	SendTo   interface{} `json:"sendto"`
+	Active   int         `json:"active"`           // 0 - enabled, 1 - disabled
+	Severity int         `json:"severity"`         // see SeverityMask
+	Period   string      `json:"period,omitempty"` // when media is active, "1-7,00:00-24:00" by default
+}
+
+type Medias []Media
+
+// SeverityMask returns media severity bitmask for given trigger severities.
+func SeverityMask(severities ...PriorityType) (mask int) {
+	for _, s := range severities {
+		mask |= 1 << uint(s)
+	}
+	return
+}
+
+// UserMediasUpdate replaces all media of user (Zabbix 4.0+).
+func (api *API) UserMediasUpdate(userId string, medias Medias) (err error) {
+	for _, m := range medias {
+		if m.Severity&^SeverityMask(NotClassified, Information, Warning, Average, High, Disaster) != 0 {
+			return fmt.Errorf("Unexpected severity mask %d.", m.Severity)
+		}
+	}
+	if medias == nil {
+		medias = Medias{}
+	}
+
+	// renamed in Zabbix 5.2
+	name := "user_medias"
+	ok, err := api.versionAtLeast(5, 2)
+	if err != nil {
+		return
+	}
+	if ok {
+		name = "medias"
+	}
+
+	response, err := api.CallWithError("user.update", Params{"userid": userId, name: medias})
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	userids := result["userids"].([]interface{})
+	if len(userids) != 1 {
+		err = &ExpectedMore{1, len(userids)}
+	}
+	return
+}
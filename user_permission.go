@@ -0,0 +1,99 @@
+package zabbix
+
+// HostPermission is an effective access of user to host.
+type HostPermission struct {
+	Permission PermissionType
+	SuperAdmin bool       // super admins have read-write access to all hosts
+	TagFilters TagFilters // problems of host visible to user, empty if all problems are visible
+}
+
+// UserHostPermission computes effective permission of user to host as Zabbix does: deny for any host group
+// of the host in any user group takes precedence, otherwise the highest permission wins.
+// Without permissions to host groups access is denied.
+func (api *API) UserHostPermission(userId, hostId string) (res HostPermission, err error) {
+	user, err := api.UserGetById(userId)
+	if err != nil {
+		return
+	}
+	if user.Type == UserTypeSuperAdmin {
+		return HostPermission{Permission: PermissionReadWrite, SuperAdmin: true}, nil
+	}
+	if user.RoleId != "" {
+		var role *Role
+		if role, err = api.RoleGetById(user.RoleId); err != nil {
+			return
+		}
+		if role.Type == UserTypeSuperAdmin {
+			return HostPermission{Permission: PermissionReadWrite, SuperAdmin: true}, nil
+		}
+	}
+	if len(user.UserGroups) == 0 {
+		return
+	}
+
+	hostGroups, err := api.HostGroupsGet(Params{"hostids": hostId})
+	if err != nil {
+		return
+	}
+	isHostGroup := make(map[string]bool, len(hostGroups))
+	for _, g := range hostGroups {
+		isHostGroup[g.GroupId] = true
+	}
+
+	ids := make([]string, len(user.UserGroups))
+	for i, g := range user.UserGroups {
+		ids[i] = g.UserGroupId
+	}
+	selectRights := "selectRights"
+	newRights, err := api.versionAtLeast(6, 2)
+	if err != nil {
+		return
+	}
+	if newRights {
+		selectRights = "selectHostGroupRights"
+	}
+	groups, err := api.UserGroupsGet(Params{"usrgrpids": ids, selectRights: "extend", "selectTagFilters": "extend"})
+	if err != nil {
+		return
+	}
+
+	denied, granted, unfiltered := false, false, false
+	var filters TagFilters
+	for _, g := range groups {
+		rights := g.Rights
+		if newRights {
+			rights = g.HostGroupRights
+		}
+		filtered := make(map[string]bool)
+		for _, f := range g.TagFilters {
+			if isHostGroup[f.GroupId] {
+				filtered[f.GroupId] = true
+				filters = append(filters, f)
+			}
+		}
+		for _, r := range rights {
+			if !isHostGroup[r.Id] {
+				continue
+			}
+			if r.Permission == PermissionDeny {
+				denied = true
+				continue
+			}
+			granted = true
+			if r.Permission > res.Permission {
+				res.Permission = r.Permission
+			}
+			if !filtered[r.Id] {
+				unfiltered = true
+			}
+		}
+	}
+
+	if denied || !granted {
+		return HostPermission{Permission: PermissionDeny}, nil
+	}
+	if !unfiltered {
+		res.TagFilters = filters
+	}
+	return
+}
@@ -0,0 +1,83 @@
+package zabbix_test
+
+import (
+	"testing"
+
+	. "."
+)
+
+func TestUsers(t *testing.T) {
+	api, requests := fakeAPI(t, map[string]string{
+		"user.create":      `{"userids": ["5"]}`,
+		"usergroup.create": `{"usrgrpids": ["7"]}`,
+		"role.get":         `[{"roleid": "4", "name": "Operator", "type": "1", "readonly": "0", "rules": {"ui": [{"name": "monitoring.problems", "status": "1"}], "ui.default_access": "0", "api.access": "1", "api.mode": "1", "api": ["problem.get"], "actions.default_access": "1"}}]`,
+	})
+
+	enabled := 0
+	groups := UserGroups{{
+		Name:        "Operators",
+		UsersStatus: &enabled,
+		Rights:      Permissions{{Id: "2", Permission: PermissionRead}},
+		TagFilters:  TagFilters{{GroupId: "2", Tag: "service", Value: "db"}},
+	}}
+	if err := api.UserGroupsCreate(groups); err != nil || groups[0].UserGroupId != "7" {
+		t.Fatalf("Unexpected user group %#v: %v", groups[0], err)
+	}
+	if params := (*requests)[0]["params"].([]interface{})[0].(map[string]interface{}); params["users_status"] != 0.0 || params["gui_access"] != nil {
+		t.Errorf("Unexpected params %v", params)
+	}
+
+	disabled := 0
+	users := Users{{Username: "jdoe", Password: "secret", RoleId: "4", AutoLogin: &disabled, UserGroups: UserGroupIds{{UserGroupId: "7"}}}}
+	if err := api.UsersCreate(users); err != nil || users[0].UserId != "5" {
+		t.Fatalf("Unexpected user %#v: %v", users[0], err)
+	}
+	params := (*requests)[1]["params"].([]interface{})[0].(map[string]interface{})
+	group := params["usrgrps"].([]interface{})[0].(map[string]interface{})
+	if len(group) != 1 || group["usrgrpid"] != "7" || params["type"] != nil || params["autologin"] != 0.0 {
+		t.Errorf("Unexpected params %v", params)
+	}
+
+	role, err := api.RoleGetById("4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if role.Type != UserTypeUser || role.Rules.APIMode != 1 || role.Rules.API[0] != "problem.get" || role.Rules.UI[0].Status != 1 || role.Rules.ActionsDefaultAccess != 1 {
+		t.Errorf("Unexpected role %#v %#v", role, role.Rules)
+	}
+}
+
+func TestUserHostPermission(t *testing.T) {
+	results := map[string]string{
+		"APIInfo.version": `"6.0.0"`,
+		"user.get":        `[{"userid": "5", "username": "jdoe", "roleid": "4", "usrgrps": [{"usrgrpid": "7"}, {"usrgrpid": "8"}]}]`,
+		"role.get":        `[{"roleid": "4", "type": "2"}]`,
+		"usergroup.get": `[
+			{"usrgrpid": "7", "rights": [{"id": "1", "permission": "2"}], "tag_filters": [{"groupid": "1", "tag": "service", "value": "db"}]},
+			{"usrgrpid": "8", "rights": [{"id": "2", "permission": "3"}, {"id": "3", "permission": "0"}], "tag_filters": []}
+		]`,
+	}
+	for groups, expected := range map[string]HostPermission{
+		`[{"groupid": "1"}, {"groupid": "2"}]`: {Permission: PermissionReadWrite},
+		`[{"groupid": "1"}]`:                   {Permission: PermissionRead, TagFilters: TagFilters{{GroupId: "1", Tag: "service", Value: "db"}}},
+		`[{"groupid": "2"}, {"groupid": "3"}]`: {Permission: PermissionDeny},
+		`[{"groupid": "4"}]`:                   {Permission: PermissionDeny},
+	} {
+		results["hostgroup.get"] = groups
+		api, _ := fakeAPI(t, results)
+		p, err := api.UserHostPermission("5", "10084")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.Permission != expected.Permission || len(p.TagFilters) != len(expected.TagFilters) ||
+			len(p.TagFilters) > 0 && p.TagFilters[0] != expected.TagFilters[0] {
+			t.Errorf("%s: expected %#v, got %#v", groups, expected, p)
+		}
+	}
+
+	results["role.get"] = `[{"roleid": "4", "type": "3"}]`
+	api, _ := fakeAPI(t, results)
+	if p, err := api.UserHostPermission("5", "10084"); err != nil || !p.SuperAdmin || p.Permission != PermissionReadWrite {
+		t.Errorf("Expected super admin, got %#v: %v", p, err)
+	}
+}
@@ -0,0 +1,150 @@
+package zabbix
+
+type PermissionType int
+
+const (
+	PermissionDeny      PermissionType = 0
+	PermissionRead      PermissionType = 2
+	PermissionReadWrite PermissionType = 3
+)
+
+// Permission is an access of user group to host group (or template group): https://www.zabbix.com/documentation/4.0/manual/api/reference/usergroup/object#permission
+type Permission struct {
+	Id         string         `json:"id"`
+	Permission PermissionType `json:"permission"`
+}
+
+type Permissions []Permission
+
+// TagFilter limits problems of host group visible to user group to ones with given tag and value (Zabbix 4.0+).
+type TagFilter struct {
+	GroupId string `json:"groupid"`
+	Tag     string `json:"tag,omitempty"`
+	Value   string `json:"value,omitempty"`
+}
+
+type TagFilters []TagFilter
+
+// UserGroup struct from https://www.zabbix.com/documentation/4.0/manual/api/reference/usergroup/object
+type UserGroup struct {
+	UserGroupId string `json:"usrgrpid,omitempty"`
+	Name        string `json:"name,omitempty"`
+
+	// Pointers are nil to keep values unchanged, as user groups of users are sent only with usrgrpid.
+	GuiAccess   *int `json:"gui_access,omitempty"`   // 0 - system default, 1 - internal, 2 - LDAP, 3 - disabled
+	UsersStatus *int `json:"users_status,omitempty"` // 0 - enabled, 1 - disabled
+	DebugMode   *int `json:"debug_mode,omitempty"`   // 0 - disabled, 1 - enabled
+
+	Rights              Permissions `json:"rights,omitempty"`               // host groups before Zabbix 6.2
+	HostGroupRights     Permissions `json:"hostgroup_rights,omitempty"`     // Zabbix 6.2+
+	TemplateGroupRights Permissions `json:"templategroup_rights,omitempty"` // Zabbix 6.2+
+	TagFilters          TagFilters  `json:"tag_filters,omitempty"`
+	UserIds             []string    `json:"userids,omitempty"` // only for create and update before Zabbix 5.2
+	Users               []UserId    `json:"users,omitempty"`   // only for create and update, Zabbix 5.2+
+}
+
+type UserGroups []UserGroup
+
+type UserGroupId struct {
+	UserGroupId string `json:"usrgrpid"`
+}
+
+type UserGroupIds []UserGroupId
+
+type UserId struct {
+	UserId string `json:"userid"`
+}
+
+// UserGroupsGet is a wrapper for usergroup.get: https://www.zabbix.com/documentation/4.0/manual/api/reference/usergroup/get
+// Use "selectRights" ("selectHostGroupRights" in Zabbix 6.2+) and "selectTagFilters" to get permissions.
+func (api *API) UserGroupsGet(params Params) (res UserGroups, err error) {
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	response, err := api.CallWithError("usergroup.get", params)
+	if err != nil {
+		return
+	}
+
+	err = decode(response.Result.([]interface{}), &res)
+	return
+}
+
+// UserGroupGetById gets user group by Id only if there is exactly 1 matching user group.
+func (api *API) UserGroupGetById(id string) (res *UserGroup, err error) {
+	groups, err := api.UserGroupsGet(Params{"usrgrpids": id})
+	if err != nil {
+		return
+	}
+
+	if len(groups) == 1 {
+		res = &groups[0]
+	} else {
+		e := ExpectedOneResult(len(groups))
+		err = &e
+	}
+	return
+}
+
+// UserGroupsCreate is a wrapper for usergroup.create: https://www.zabbix.com/documentation/4.0/manual/api/reference/usergroup/create
+func (api *API) UserGroupsCreate(groups UserGroups) (err error) {
+	response, err := api.CallWithError("usergroup.create", groups)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	usrgrpids := result["usrgrpids"].([]interface{})
+	for i, id := range usrgrpids {
+		groups[i].UserGroupId = id.(string)
+	}
+	return
+}
+
+// UserGroupsUpdate is a wrapper for usergroup.update: https://www.zabbix.com/documentation/4.0/manual/api/reference/usergroup/update
+// Non-empty rights, tag filters and users replace existing ones.
+func (api *API) UserGroupsUpdate(groups UserGroups) (err error) {
+	response, err := api.CallWithError("usergroup.update", groups)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	usrgrpids := result["usrgrpids"].([]interface{})
+	if len(groups) != len(usrgrpids) {
+		err = &ExpectedMore{len(groups), len(usrgrpids)}
+	}
+	return
+}
+
+// UserGroupsDelete is a wrapper for usergroup.delete: https://www.zabbix.com/documentation/4.0/manual/api/reference/usergroup/delete
+// Cleans UserGroupId in all user groups elements if call succeed.
+func (api *API) UserGroupsDelete(groups UserGroups) (err error) {
+	ids := make([]string, len(groups))
+	for i, group := range groups {
+		ids[i] = group.UserGroupId
+	}
+
+	err = api.UserGroupsDeleteByIds(ids)
+	if err == nil {
+		for i := range groups {
+			groups[i].UserGroupId = ""
+		}
+	}
+	return
+}
+
+// UserGroupsDeleteByIds is a wrapper for usergroup.delete: https://www.zabbix.com/documentation/4.0/manual/api/reference/usergroup/delete
+func (api *API) UserGroupsDeleteByIds(ids []string) (err error) {
+	response, err := api.CallWithError("usergroup.delete", ids)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	usrgrpids := result["usrgrpids"].([]interface{})
+	if len(ids) != len(usrgrpids) {
+		err = &ExpectedMore{len(ids), len(usrgrpids)}
+	}
+	return
+}
@@ -0,0 +1,268 @@
+package zabbix
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// WebhookSecretHeader carries shared secret of webhook media type created by CreateWebhookMediaType.
+const WebhookSecretHeader = "X-Zabbix-Secret"
+
+// WebhookPayloadTemplate is a default payload of webhook media type: fields of WebhookPayload and their macros.
+// Webhook script sends all parameters except "url" and "secret" as JSON object.
+var WebhookPayloadTemplate = MediaTypeParameters{
+	{Name: "event_id", Value: "{EVENT.ID}"},
+	{Name: "event_name", Value: "{EVENT.NAME}"},
+	{Name: "event_value", Value: "{EVENT.VALUE}"},
+	{Name: "event_update", Value: "{EVENT.UPDATE.STATUS}"},
+	{Name: "event_status", Value: "{EVENT.STATUS}"},
+	{Name: "event_date", Value: "{EVENT.DATE}"},
+	{Name: "event_time", Value: "{EVENT.TIME}"},
+	{Name: "event_severity", Value: "{EVENT.NSEVERITY}"},
+	{Name: "event_tags", Value: "{EVENT.TAGSJSON}"},
+	{Name: "event_ack", Value: "{EVENT.ACK.STATUS}"},
+	{Name: "recovery_id", Value: "{EVENT.RECOVERY.ID}"},
+	{Name: "update_message", Value: "{EVENT.UPDATE.MESSAGE}"},
+	{Name: "trigger_id", Value: "{TRIGGER.ID}"},
+	{Name: "trigger_name", Value: "{TRIGGER.NAME}"},
+	{Name: "trigger_status", Value: "{TRIGGER.STATUS}"},
+	{Name: "host_id", Value: "{HOST.ID}"},
+	{Name: "host", Value: "{HOST.HOST}"},
+	{Name: "host_name", Value: "{HOST.NAME}"},
+}
+
+// WebhookScript is a JavaScript of webhook media type posting payload to "url" parameter.
+// It uses HttpRequest of Zabbix 5.4+, or CurlHttpRequest before.
+const WebhookScript = `var params = JSON.parse(value),
+    payload = {},
+    req, resp, status;
+
+for (var name in params) {
+    if (name !== 'url' && name !== 'secret') {
+        payload[name] = params[name];
+    }
+}
+if (typeof HttpRequest !== 'undefined') {
+    req = new HttpRequest();
+    req.addHeader('Content-Type: application/json');
+    req.addHeader('` + WebhookSecretHeader + `: ' + params.secret);
+    resp = req.post(params.url, JSON.stringify(payload));
+    status = req.getStatus();
+} else {
+    req = new CurlHttpRequest();
+    req.AddHeader('Content-Type: application/json');
+    req.AddHeader('` + WebhookSecretHeader + `: ' + params.secret);
+    resp = req.Post(params.url, JSON.stringify(payload));
+    status = req.Status();
+}
+if (status !== 200) {
+    throw 'Response code ' + status + ': ' + resp;
+}
+return 'OK';`
+
+// WebhookPayload is a body of request sent by webhook media type with WebhookPayloadTemplate.
+// Values are expanded macros; unresolved macros are kept as is by Zabbix and treated as empty.
+type WebhookPayload struct {
+	EventId       string `json:"event_id"`
+	EventName     string `json:"event_name"`
+	EventValue    string `json:"event_value"`  // "1" for problem, "0" for recovery
+	EventUpdate   string `json:"event_update"` // "1" for problem update, like acknowledgement
+	EventStatus   string `json:"event_status"` // "PROBLEM" or "RESOLVED"
+	EventDate     string `json:"event_date"`   // 2021.03.10
+	EventTime     string `json:"event_time"`   // 12:00:00
+	EventSeverity string `json:"event_severity"`
+	EventTags     string `json:"event_tags"` // JSON array, Zabbix 5.0+
+	EventAck      string `json:"event_ack"`  // "Yes" or "No"
+	RecoveryId    string `json:"recovery_id"`
+	UpdateMessage string `json:"update_message"`
+	TriggerId     string `json:"trigger_id"`
+	TriggerName   string `json:"trigger_name"`
+	TriggerStatus string `json:"trigger_status"` // "PROBLEM" or "OK"
+	HostId        string `json:"host_id"`
+	Host          string `json:"host"`
+	HostName      string `json:"host_name"`
+}
+
+// WebhookAlert is a decoded webhook request.
+type WebhookAlert struct {
+	Event   Event
+	Trigger Trigger
+	Host    Host
+	Tags    ProblemTags
+	Update  bool   // problem update: acknowledgement, message or severity change
+	Message string // update message
+	Payload WebhookPayload
+}
+
+// Resolved checks if alert is about recovery.
+func (a *WebhookAlert) Resolved() bool {
+	return a.Event.Value == TriggerOk
+}
+
+// Returns value, or empty string for unresolved macro.
+func macroValue(s string) string {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}") && !strings.Contains(s, "\"") {
+		return ""
+	}
+	return s
+}
+
+// Decode converts payload to alert. Event date and time are parsed in loc of Zabbix server.
+func (p *WebhookPayload) Decode(loc *time.Location) (a WebhookAlert, err error) {
+	a.Payload = *p
+	id := macroValue(p.EventId)
+	if id == "" {
+		return a, fmt.Errorf("No event ID in payload.")
+	}
+
+	value := TriggerProblem
+	if macroValue(p.EventValue) == "0" || p.EventStatus == "RESOLVED" {
+		value = TriggerOk
+	}
+	var severity PriorityType
+	if s := macroValue(p.EventSeverity); s != "" {
+		if _, err = fmt.Sscan(s, &severity); err != nil {
+			return a, fmt.Errorf("Unexpected severity %q.", s)
+		}
+	}
+	var clock int64
+	if d, t := macroValue(p.EventDate), macroValue(p.EventTime); d != "" && t != "" {
+		tm, err := time.ParseInLocation("2006.01.02 15:04:05", d+" "+t, loc)
+		if err != nil {
+			return a, err
+		}
+		clock = tm.Unix()
+	}
+	if tags := macroValue(p.EventTags); tags != "" {
+		if err = json.Unmarshal([]byte(tags), &a.Tags); err != nil {
+			return a, fmt.Errorf("Unexpected tags %q: %s", tags, err)
+		}
+	}
+
+	a.Host = Host{HostId: macroValue(p.HostId), Host: macroValue(p.Host), Name: macroValue(p.HostName)}
+	a.Trigger = Trigger{
+		TriggerId:   macroValue(p.TriggerId),
+		Description: macroValue(p.TriggerName),
+		Priority:    severity,
+		Hosts:       Hosts{a.Host},
+	}
+	if p.TriggerStatus == "PROBLEM" {
+		a.Trigger.Value = TriggerProblem
+	}
+	a.Event = Event{
+		EventId:  id,
+		Clock:    clock,
+		Source:   SourceTrigger,
+		Object:   ObjectTrigger,
+		ObjectId: a.Trigger.TriggerId,
+		Value:    value,
+		Name:     macroValue(p.EventName),
+		Severity: severity,
+		REventId: macroValue(p.RecoveryId),
+		Triggers: Triggers{a.Trigger},
+		Hosts:    Hosts{a.Host},
+	}
+	if p.EventAck == "Yes" {
+		a.Event.Acknowledged = 1
+	}
+	a.Update = macroValue(p.EventUpdate) == "1"
+	a.Message = macroValue(p.UpdateMessage)
+	return
+}
+
+// WebhookHandler is http.Handler receiving alerts of webhook media type created by CreateWebhookMediaType.
+// Requests without valid secret are rejected with 401, all requests are rejected if Secret is empty.
+// Handler errors are returned with 500 and shown by Zabbix as alert delivery errors.
+type WebhookHandler struct {
+	Secret   string
+	Location *time.Location // of Zabbix server, time.Local by default
+
+	problem, resolved, update []func(*WebhookAlert) error
+}
+
+// NewWebhookHandler creates handler validating secret.
+func NewWebhookHandler(secret string) *WebhookHandler {
+	return &WebhookHandler{Secret: secret}
+}
+
+// OnProblem adds handler of new problems.
+func (h *WebhookHandler) OnProblem(f func(*WebhookAlert) error) *WebhookHandler {
+	h.problem = append(h.problem, f)
+	return h
+}
+
+// OnResolved adds handler of recoveries.
+func (h *WebhookHandler) OnResolved(f func(*WebhookAlert) error) *WebhookHandler {
+	h.resolved = append(h.resolved, f)
+	return h
+}
+
+// OnUpdate adds handler of problem updates.
+func (h *WebhookHandler) OnUpdate(f func(*WebhookAlert) error) *WebhookHandler {
+	h.update = append(h.update, f)
+	return h
+}
+
+// ServeHTTP implements http.Handler.
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed.", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.Secret == "" || subtle.ConstantTimeCompare([]byte(r.Header.Get(WebhookSecretHeader)), []byte(h.Secret)) != 1 {
+		http.Error(w, "Invalid secret.", http.StatusUnauthorized)
+		return
+	}
+
+	var p WebhookPayload
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	loc := h.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	a, err := p.Decode(loc)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	handlers := h.problem
+	switch {
+	case a.Update:
+		handlers = h.update
+	case a.Resolved():
+		handlers = h.resolved
+	}
+	for _, f := range handlers {
+		if err = f(&a); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	w.Write([]byte("OK"))
+}
+
+// CreateWebhookMediaType creates webhook media type (Zabbix 5.0+) posting WebhookPayloadTemplate
+// with WebhookScript to url, and returns its ID.
+func (api *API) CreateWebhookMediaType(name, url, secret string) (id string, err error) {
+	mediaTypes := MediaTypes{{
+		Name:       name,
+		Type:       MediaWebhook,
+		Parameters: append(MediaTypeParameters{{Name: "url", Value: url}, {Name: "secret", Value: secret}}, WebhookPayloadTemplate...),
+		Script:     WebhookScript,
+		Timeout:    "30s",
+	}}
+	if err = api.MediaTypesCreate(mediaTypes); err != nil {
+		return
+	}
+	id = mediaTypes[0].MediaTypeId
+	return
+}
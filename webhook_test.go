@@ -0,0 +1,90 @@
+package zabbix_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	. "."
+)
+
+func TestWebhookHandler(t *testing.T) {
+	var problems, resolved, updates []*WebhookAlert
+	h := NewWebhookHandler("s3cret").
+		OnProblem(func(a *WebhookAlert) error { problems = append(problems, a); return nil }).
+		OnResolved(func(a *WebhookAlert) error { resolved = append(resolved, a); return nil }).
+		OnUpdate(func(a *WebhookAlert) error {
+			updates = append(updates, a)
+			return errors.New("not now")
+		})
+	h.Location = time.UTC
+
+	post := func(secret, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/zabbix", strings.NewReader(body))
+		req.Header.Set(WebhookSecretHeader, secret)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w
+	}
+
+	problem := `{"event_id": "42", "event_name": "Disk is full", "event_value": "1", "event_update": "0", "event_status": "PROBLEM",
+		"event_date": "2021.03.10", "event_time": "12:00:00", "event_severity": "4",
+		"event_tags": "[{\"tag\":\"service\",\"value\":\"db\"}]", "event_ack": "No",
+		"recovery_id": "{EVENT.RECOVERY.ID}", "update_message": "{EVENT.UPDATE.MESSAGE}",
+		"trigger_id": "13", "trigger_name": "Disk is full", "trigger_status": "PROBLEM",
+		"host_id": "10084", "host": "db-1", "host_name": "Database 1"}`
+	if w := post("wrong", problem); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401, got %d", w.Code)
+	}
+	if w := post("s3cret", "{"); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400, got %d", w.Code)
+	}
+	if w := post("s3cret", problem); w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body)
+	}
+	if len(problems) != 1 {
+		t.Fatalf("Expected problem, got %v", problems)
+	}
+	a := problems[0]
+	if a.Event.EventId != "42" || a.Event.Value != TriggerProblem || a.Event.Severity != High || a.Event.REventId != "" ||
+		a.Event.Clock != time.Date(2021, 3, 10, 12, 0, 0, 0, time.UTC).Unix() ||
+		a.Trigger.TriggerId != "13" || a.Host.Name != "Database 1" || a.Tags[0].Value != "db" || a.Resolved() {
+		t.Errorf("Unexpected alert %#v", a)
+	}
+
+	recovery := strings.NewReplacer(`"event_value": "1"`, `"event_value": "0"`, `{EVENT.RECOVERY.ID}`, `43`).Replace(problem)
+	if w := post("s3cret", recovery); w.Code != http.StatusOK || len(resolved) != 1 || resolved[0].Event.REventId != "43" {
+		t.Errorf("Unexpected recovery %d %v", w.Code, resolved)
+	}
+
+	update := strings.NewReplacer(`"event_update": "0"`, `"event_update": "1"`, `{EVENT.UPDATE.MESSAGE}`, `on it`).Replace(problem)
+	if w := post("s3cret", update); w.Code != http.StatusInternalServerError || len(updates) != 1 || updates[0].Message != "on it" {
+		t.Errorf("Unexpected update %d %v", w.Code, updates)
+	}
+
+	h.Secret = ""
+	if w := post("", problem); w.Code != http.StatusUnauthorized || len(problems) != 1 {
+		t.Errorf("Expected 401 without secret, got %d", w.Code)
+	}
+}
+
+func TestCreateWebhookMediaType(t *testing.T) {
+	api, requests := fakeAPI(t, map[string]string{"mediatype.create": `{"mediatypeids": ["5"]}`})
+	id, err := api.CreateWebhookMediaType("Pipeline", "https://example.com/zabbix", "s3cret")
+	if err != nil || id != "5" {
+		t.Fatalf("Unexpected result %q: %v", id, err)
+	}
+	params := (*requests)[0]["params"].([]interface{})[0].(map[string]interface{})
+	parameters := params["parameters"].([]interface{})
+	if script := params["script"].(string); !strings.Contains(script, "req.getStatus()") || !strings.Contains(script, "new CurlHttpRequest()") {
+		t.Errorf("Unexpected script %s", script)
+	}
+	if params["type"] != 4.0 || len(parameters) != len(WebhookPayloadTemplate)+2 ||
+		parameters[1].(map[string]interface{})["value"] != "s3cret" || parameters[0].(map[string]interface{})["sortorder"] != nil ||
+		!strings.Contains(params["script"].(string), WebhookSecretHeader) {
+		t.Errorf("Unexpected params %v", params)
+	}
+}